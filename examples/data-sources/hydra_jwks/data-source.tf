@@ -1,3 +1,7 @@
 data "hydra_jwks" "default" {
   name = "hydra.openid.id-token"
 }
+
+output "id_token_jwks" {
+  value = data.hydra_jwks.default.public_jwks_json
+}
@@ -0,0 +1,8 @@
+data "hydra_oauth2_client" "by_id" {
+  client_id = "example"
+}
+
+data "hydra_oauth2_client" "by_name" {
+  client_name = "example"
+  owner       = "team-a"
+}
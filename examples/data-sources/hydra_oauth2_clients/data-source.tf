@@ -0,0 +1,11 @@
+data "hydra_oauth2_clients" "gateway" {
+  owner = "team-a"
+
+  metadata_regex = {
+    "gateway" = "^(public|partner)$"
+  }
+}
+
+output "gateway_redirect_uris" {
+  value = { for c in data.hydra_oauth2_clients.gateway.clients : c.client_id => c.redirect_uris }
+}
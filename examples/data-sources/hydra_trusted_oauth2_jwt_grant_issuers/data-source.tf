@@ -0,0 +1,7 @@
+data "hydra_trusted_oauth2_jwt_grant_issuers" "idp" {
+  issuer = "https://idp.example.com"
+}
+
+output "idp_key_ids" {
+  value = data.hydra_trusted_oauth2_jwt_grant_issuers.idp.issuers[*].key_id
+}
@@ -0,0 +1,2 @@
+# Keys can be imported by the name of their set and their key ID, separated by a slash
+terraform import hydra_jwk.partner partner-keys/partner-a
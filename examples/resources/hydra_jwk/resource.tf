@@ -0,0 +1,11 @@
+resource "hydra_jwk" "partner" {
+  set = "partner-keys"
+
+  alg = "ES256"
+  crv = "P-256"
+  kid = "partner-a"
+  kty = "EC"
+  use = "sig"
+  x   = "f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"
+  y   = "x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"
+}
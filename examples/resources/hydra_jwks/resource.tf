@@ -12,6 +12,67 @@ resource "hydra_jwks" "generated" {
   }
 }
 
+resource "hydra_jwks" "id_token" {
+  name = "id-token"
+
+  generator {
+    alg = "RS256"
+    kid = "rs256"
+    use = "sig"
+
+    keepers = {
+      version = 1
+    }
+  }
+
+  generator {
+    alg = "ES256"
+    kid = "es256"
+    use = "sig"
+
+    keepers = {
+      version = 1
+    }
+  }
+}
+
+resource "hydra_jwks" "rotated" {
+  name = "rotated"
+
+  generator {
+    alg = "ES256"
+    kid = "signing"
+    use = "sig"
+
+    keepers = {
+      version = 1
+    }
+
+    rotation_period = "720h"
+    retain_previous = 1
+  }
+}
+
+resource "tls_private_key" "signing" {
+  algorithm   = "ECDSA"
+  ecdsa_curve = "P256"
+}
+
+resource "hydra_jwks" "imported" {
+  name = "imported"
+
+  key {
+    alg             = "ES256"
+    use             = "sig"
+    private_key_pem = tls_private_key.signing.private_key_pem
+  }
+}
+
+resource "hydra_jwks" "ceremony" {
+  name      = "ceremony"
+  jwks_json = file("${path.module}/ceremony.jwks.json")
+}
+
 resource "hydra_jwks" "inlined" {
   name = "inlined"
 
@@ -0,0 +1,6 @@
+# OAuth2 clients can be imported by client ID
+terraform import hydra_oauth2_client.example example
+
+# or looked up by client name, optionally scoped to an owner
+terraform import hydra_oauth2_client.example name:example
+terraform import hydra_oauth2_client.example owner:team-a/example
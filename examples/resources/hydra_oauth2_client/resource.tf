@@ -2,9 +2,10 @@ resource "hydra_oauth2_client" "example" {
 	client_id = "example"
 	client_name = "example"
 
-	metadata = {
+	metadata = jsonencode({
 		"first_party" = true
-	}
+		"tier"        = 1
+	})
     
 	redirect_uris = ["http://localhost:8080/callback"]
 	response_types = ["code"]
@@ -0,0 +1,3 @@
+# Client sets can be imported by the client IDs of their clients, separated by commas.
+# Imported clients are replaced with their definition in the document on the next apply.
+terraform import hydra_oauth2_client_set.partners partner-a,partner-b
@@ -0,0 +1,3 @@
+resource "hydra_oauth2_client_set" "partners" {
+  document = file("${path.module}/clients.yaml")
+}
@@ -0,0 +1,11 @@
+resource "hydra_oidc_dynamic_client" "tenant" {
+  public_endpoint = "https://hydra.localhost"
+
+  client_name   = "tenant"
+  redirect_uris = ["https://tenant.example.com/callback"]
+}
+
+output "tenant_registration_access_token" {
+  value     = hydra_oidc_dynamic_client.tenant.registration_access_token
+  sensitive = true
+}
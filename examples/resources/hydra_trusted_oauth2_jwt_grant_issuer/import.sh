@@ -0,0 +1,2 @@
+# Trusted JWT grant issuers can be imported by the ID Hydra assigned to the trust relationship
+terraform import hydra_trusted_oauth2_jwt_grant_issuer.idp 9edc811f-4e28-453c-9b46-4de65f00217f
@@ -0,0 +1,12 @@
+resource "hydra_trusted_oauth2_jwt_grant_issuer" "idp" {
+  issuer     = "https://idp.example.com"
+  subject    = "service-account@example.com"
+  scopes     = ["read", "write"]
+  expires_at = "2030-01-01T00:00:00Z"
+
+  jwk {
+    alg            = "RS256"
+    use            = "sig"
+    public_key_pem = file("${path.module}/idp.pub.pem")
+  }
+}
@@ -21,9 +21,14 @@ A JSON Web Key is identified by its set and key id. ORY Hydra uses this function
 			},
 			"keys": {
 				Type:     schema.TypeList,
-				Elem:     resourceJWK(),
+				Elem:     addJWKOutputs(resourceJWK()),
 				Computed: true,
 			},
+			"public_jwks_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON Web Key Set of the public keys in the set, without private members and symmetric keys, e.g. to be published to relying parties.",
+			},
 		},
 		ReadContext: readJWKSDataSource,
 	}
@@ -47,7 +52,5 @@ func readJWKSDataSource(ctx context.Context, data *schema.ResourceData, meta int
 		return diag.FromErr(err)
 	}
 
-	dataFromJWKS(data, jsonWebKeySet, "keys")
-
-	return nil
+	return diag.FromErr(dataFromPublicJWKS(data, jsonWebKeySet, "keys"))
 }
@@ -1,9 +1,18 @@
 package provider
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccDataSourceJWKS(t *testing.T) {
@@ -24,6 +33,37 @@ func TestAccDataSourceJWKS(t *testing.T) {
 	})
 }
 
+func TestDataSourceJWKS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signing := &hydra.JsonWebKey{Alg: "RS256", Use: "sig"}
+	require.NoError(t, setJWKFromPrivateKeyPEM(signing, string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})), ""))
+	hmac := hydra.JsonWebKey{Alg: "HS256", Kid: "hmac", Kty: "oct", Use: "sig", K: ptr("c2VjcmV0")}
+
+	meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/admin/keys/signing", req.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(hydra.JsonWebKeySet{Keys: []hydra.JsonWebKey{*signing, hmac}})
+	}))
+
+	data := dataSourceJWKS().TestResourceData()
+	require.NoError(t, data.Set("name", "signing"))
+
+	require.False(t, readJWKSDataSource(context.Background(), data, meta).HasError())
+	require.Equal(t, 2, data.Get("keys.#"))
+	require.Equal(t, signing.Kid, data.Get("keys.0.thumbprint_sha256"))
+	require.Equal(t, "", data.Get("keys.1.public_key_pem"))
+
+	block, _ := pem.Decode([]byte(data.Get("keys.0.public_key_pem").(string)))
+	require.NotNil(t, block)
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	require.True(t, privateKey.PublicKey.Equal(publicKey))
+
+	require.JSONEq(t, `{"keys": [{"alg": "RS256", "kid": "`+signing.Kid+`", "kty": "RSA", "use": "sig", "n": "`+signing.GetN()+`", "e": "AQAB"}]}`, data.Get("public_jwks_json").(string))
+}
+
 const (
 	testAccDataSourceJWKSConfig = `
 provider "hydra" {
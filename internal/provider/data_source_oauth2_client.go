@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+// oAuth2ClientDataSourceOmittedAttributes are left out of the data source. Hydra never returns secrets once a client
+// is created, and profiles and verification only exist in configuration.
+var oAuth2ClientDataSourceOmittedAttributes = []string{
+	"client_secret",
+	"profile",
+	"registration_access_token",
+	"verify",
+}
+
+func dataSourceOAuth2Client() *schema.Resource {
+	s := computedSchema(resourceOAuth2Client().Schema)
+	for _, key := range oAuth2ClientDataSourceOmittedAttributes {
+		delete(s, key)
+	}
+
+	// PEM inputs only exist in configuration, the keys are exposed through their JWK members.
+	jwk := s["jwk"].Elem.(*schema.Resource)
+	delete(jwk.Schema, "public_key_pem")
+	delete(jwk.Schema, "certificate_pem")
+
+	s["client_id"].Optional = true
+	s["client_id"].ExactlyOneOf = []string{"client_id", "client_name"}
+	s["client_name"].Optional = true
+	s["owner"].Optional = true
+	s["owner"].ConflictsWith = []string{"client_id"}
+	s["owner"].Description = "Owner is a string identifying the owner of the OAuth 2.0 Client. Narrows down the lookup by client_name."
+
+	return &schema.Resource{
+		Description: `Looks up an existing OAuth 2.0 client either by its client_id, or by its client_name and optionally owner.
+Looking up by name fails unless exactly one client matches.`,
+		Schema:      s,
+		ReadContext: readOAuth2ClientDataSource,
+	}
+}
+
+func readOAuth2ClientDataSource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var oAuth2Client *hydra.OAuth2Client
+
+	if clientID, ok := data.GetOk("client_id"); ok {
+		hydraClient := meta.(*ClientConfig).hydraClient
+
+		err := retryThrottledHydraAction(func() (*http.Response, error) {
+			var err error
+			var resp *http.Response
+			oAuth2Client, resp, err = hydraClient.OAuth2Api.GetOAuth2Client(ctx, clientID.(string)).Execute()
+			return resp, err
+		}, meta.(*ClientConfig).backOff)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		var err error
+		oAuth2Client, err = findOAuth2Client(ctx, meta, data.Get("client_name").(string), data.Get("owner").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return diag.FromErr(dataFromClient(data, oAuth2Client, oAuth2ClientDataSourceOmittedAttributes...))
+}
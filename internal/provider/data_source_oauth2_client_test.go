@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceOAuth2Client(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceOAuth2ClientConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.hydra_oauth2_client.by_id", "client_name", "hydra_oauth2_client.example", "client_name"),
+					checkResourceAttrJSON("data.hydra_oauth2_client.by_id", "metadata", `{"first_party": true}`),
+					resource.TestCheckTypeSetElemAttr("data.hydra_oauth2_client.by_id", "redirect_uris.*", "http://localhost:8080/callback"),
+					resource.TestCheckResourceAttr("data.hydra_oauth2_client.by_id", "token_endpoint_auth_method", "none"),
+					resource.TestCheckResourceAttrPair("data.hydra_oauth2_client.by_name", "client_id", "hydra_oauth2_client.example", "client_id"),
+					resource.TestCheckResourceAttr("data.hydra_oauth2_client.by_name", "owner", "data-source"),
+				),
+			},
+		},
+	})
+}
+
+const (
+	testAccDataSourceOAuth2ClientConfig = `
+provider "hydra" {
+  endpoint = "http://localhost:4445"
+}
+
+resource "hydra_oauth2_client" "example" {
+	client_name = "data-source"
+	owner       = "data-source"
+
+	metadata = jsonencode({
+		"first_party" = true
+	})
+
+	redirect_uris = ["http://localhost:8080/callback"]
+	response_types = ["code"]
+	token_endpoint_auth_method = "none"
+}
+
+data "hydra_oauth2_client" "by_id" {
+	client_id = hydra_oauth2_client.example.client_id
+}
+
+data "hydra_oauth2_client" "by_name" {
+	client_name = hydra_oauth2_client.example.client_name
+	owner       = hydra_oauth2_client.example.owner
+}`
+)
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+func dataSourceOAuth2Clients() *schema.Resource {
+	return &schema.Resource{
+		Description: `Lists existing OAuth 2.0 clients, following the pagination of the Admin API.
+Clients can be filtered by client_name and owner on the server, and by regular expressions on their metadata.`,
+		Schema: map[string]*schema.Schema{
+			"client_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list clients with this exact name.",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list clients of this owner.",
+			},
+			"metadata_regex": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				ValidateFunc: validateRegexpMap,
+				Description:  "Only list clients whose metadata values match all of these regular expressions, by metadata key. Values which aren't strings are matched in their JSON encoding.",
+			},
+			"client_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "IDs of the listed clients.",
+			},
+			"clients": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"client_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"client_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"owner": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"redirect_uris": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+		ReadContext: readOAuth2ClientsDataSource,
+	}
+}
+
+func readOAuth2ClientsDataSource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clientName := data.Get("client_name").(string)
+	owner := data.Get("owner").(string)
+
+	metadataRegex := make(map[string]*regexp.Regexp)
+	for k, v := range data.Get("metadata_regex").(map[string]interface{}) {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		metadataRegex[k] = re
+	}
+
+	clients, err := listOAuth2Clients(ctx, meta, clientName, owner)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	clientIDs := make([]string, 0, len(clients))
+	compactClients := make([]map[string]interface{}, 0, len(clients))
+	for _, client := range clients {
+		match, err := matchClientMetadata(&client, metadataRegex)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !match {
+			continue
+		}
+
+		clientIDs = append(clientIDs, client.GetClientId())
+		compactClients = append(compactClients, map[string]interface{}{
+			"client_id":     client.GetClientId(),
+			"client_name":   client.GetClientName(),
+			"owner":         client.GetOwner(),
+			"redirect_uris": client.RedirectUris,
+		})
+	}
+
+	data.SetId(oAuth2ClientsDataSourceID(clientName, owner, metadataRegex))
+	data.Set("client_ids", clientIDs)
+	data.Set("clients", compactClients)
+
+	return nil
+}
+
+// matchClientMetadata reports whether every regular expression matches the metadata value under its key.
+// Clients without the key don't match.
+func matchClientMetadata(client *hydra.OAuth2Client, metadataRegex map[string]*regexp.Regexp) (bool, error) {
+	if len(metadataRegex) == 0 {
+		return true, nil
+	}
+
+	metadata, _ := client.Metadata.(map[string]interface{})
+	for k, re := range metadataRegex {
+		v, ok := metadata[k]
+		if !ok {
+			return false, nil
+		}
+		value, err := metadataValueString(v)
+		if err != nil {
+			return false, err
+		}
+		if !re.MatchString(value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func oAuth2ClientsDataSourceID(clientName, owner string, metadataRegex map[string]*regexp.Regexp) string {
+	filters := []string{"client_name=" + clientName, "owner=" + owner}
+	for k, re := range metadataRegex {
+		filters = append(filters, fmt.Sprintf("metadata.%s=%s", k, re))
+	}
+	sort.Strings(filters[2:])
+	return strconv.Itoa(schema.HashString(strings.Join(filters, "\n")))
+}
+
+func validateRegexpMap(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(map[string]interface{})
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be map", key))
+		return
+	}
+
+	for k, expr := range v {
+		if _, err := regexp.Compile(expr.(string)); err != nil {
+			errors = append(errors, fmt.Errorf("%q contains an invalid regular expression for %q: %s", key, k, err))
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceOAuth2Clients(t *testing.T) {
+	var owners []string
+	meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		owners = append(owners, req.URL.Query().Get("owner"))
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Query().Get("page_token") {
+		case "":
+			w.Header().Set("Link", `</admin/clients?page_size=100&page_token=next>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"client_id": "a1", "client_name": "gateway-a", "owner": "team-a", "redirect_uris": ["https://a.example.com/callback"], "metadata": {"gateway": "public", "tier": 1}}]`))
+		default:
+			_, _ = w.Write([]byte(`[{"client_id": "b2", "client_name": "gateway-b", "owner": "team-a", "metadata": {"gateway": "internal", "tier": 2}}, {"client_id": "c3", "owner": "team-a"}]`))
+		}
+	}))
+
+	t.Run("case=lists all pages", func(t *testing.T) {
+		owners = nil
+		data := dataSourceOAuth2Clients().TestResourceData()
+		require.NoError(t, data.Set("owner", "team-a"))
+
+		require.False(t, readOAuth2ClientsDataSource(context.Background(), data, meta).HasError())
+		require.Equal(t, []string{"team-a", "team-a"}, owners)
+		require.Equal(t, []interface{}{"a1", "b2", "c3"}, data.Get("client_ids"))
+		require.Equal(t, "gateway-a", data.Get("clients.0.client_name"))
+		require.Equal(t, "team-a", data.Get("clients.0.owner"))
+		require.Equal(t, []interface{}{"https://a.example.com/callback"}, data.Get("clients.0.redirect_uris"))
+		require.NotEmpty(t, data.Id())
+	})
+
+	t.Run("case=filters by metadata", func(t *testing.T) {
+		data := dataSourceOAuth2Clients().TestResourceData()
+		require.NoError(t, data.Set("metadata_regex", map[string]interface{}{
+			"gateway": "^(public|internal)$",
+			"tier":    "^2$",
+		}))
+
+		require.False(t, readOAuth2ClientsDataSource(context.Background(), data, meta).HasError())
+		require.Equal(t, []interface{}{"b2"}, data.Get("client_ids"))
+		require.Equal(t, 1, data.Get("clients.#"))
+	})
+}
+
+func TestValidateRegexpMap(t *testing.T) {
+	_, errs := validateRegexpMap(map[string]interface{}{"gateway": "^public$"}, "metadata_regex")
+	require.Empty(t, errs)
+
+	_, errs = validateRegexpMap(map[string]interface{}{"gateway": "("}, "metadata_regex")
+	require.Len(t, errs, 1)
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceTrustedOAuth2JwtGrantIssuers() *schema.Resource {
+	return &schema.Resource{
+		Description: `Lists the issuers trusted to sign JWT assertions of the RFC 7523 JWT Profile for OAuth 2.0 Authorization Grants,
+following the pagination of the Admin API.`,
+		Schema: map[string]*schema.Schema{
+			"issuer": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list trust relationships of this issuer.",
+			},
+			"issuers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issuer": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"subject": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"allow_any_subject": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"scopes": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"expires_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key_set": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"key_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		ReadContext: readTrustedOAuth2JwtGrantIssuersDataSource,
+	}
+}
+
+func readTrustedOAuth2JwtGrantIssuersDataSource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	issuer := data.Get("issuer").(string)
+
+	trusted, err := listTrustedOAuth2JwtGrantIssuers(ctx, meta, issuer)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	issuers := make([]map[string]interface{}, len(trusted))
+	for i, t := range trusted {
+		expiresAt, createdAt := "", ""
+		if t.ExpiresAt != nil {
+			expiresAt = t.ExpiresAt.Format(time.RFC3339)
+		}
+		if t.CreatedAt != nil {
+			createdAt = t.CreatedAt.Format(time.RFC3339)
+		}
+
+		issuers[i] = map[string]interface{}{
+			"id":                t.GetId(),
+			"issuer":            t.GetIssuer(),
+			"subject":           t.GetSubject(),
+			"allow_any_subject": t.GetAllowAnySubject(),
+			"scopes":            t.Scope,
+			"expires_at":        expiresAt,
+			"created_at":        createdAt,
+			"key_set":           t.PublicKey.GetSet(),
+			"key_id":            t.PublicKey.GetKid(),
+		}
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(issuer)))
+	data.Set("issuers", issuers)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSourceTrustedOAuth2JwtGrantIssuers(t *testing.T) {
+	meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/admin/trust/grants/jwt-bearer/issuers", req.URL.Path)
+		require.Equal(t, "https://idp.example.com", req.URL.Query().Get("issuer"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Query().Get("page_token") {
+		case "":
+			w.Header().Set("Link", `</admin/trust/grants/jwt-bearer/issuers?page_size=100&page_token=next>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id": "a", "issuer": "https://idp.example.com", "subject": "alice", "scope": ["read"], "expires_at": "2030-01-01T00:00:00Z", "created_at": "2024-01-01T00:00:00Z", "public_key": {"set": "https://idp.example.com", "kid": "key-1"}}]`))
+		default:
+			_, _ = w.Write([]byte(`[{"id": "b", "issuer": "https://idp.example.com", "allow_any_subject": true, "scope": [], "expires_at": "2031-01-01T00:00:00Z", "public_key": {"set": "https://idp.example.com", "kid": "key-2"}}]`))
+		}
+	}))
+
+	data := dataSourceTrustedOAuth2JwtGrantIssuers().TestResourceData()
+	require.NoError(t, data.Set("issuer", "https://idp.example.com"))
+
+	require.False(t, readTrustedOAuth2JwtGrantIssuersDataSource(context.Background(), data, meta).HasError())
+	require.Equal(t, 2, data.Get("issuers.#"))
+	require.Equal(t, "alice", data.Get("issuers.0.subject"))
+	require.Equal(t, []interface{}{"read"}, data.Get("issuers.0.scopes"))
+	require.Equal(t, "2030-01-01T00:00:00Z", data.Get("issuers.0.expires_at"))
+	require.Equal(t, "key-1", data.Get("issuers.0.key_id"))
+	require.Equal(t, true, data.Get("issuers.1.allow_any_subject"))
+	require.Equal(t, "key-2", data.Get("issuers.1.key_id"))
+	require.Equal(t, "", data.Get("issuers.1.created_at"))
+}
@@ -0,0 +1,260 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// GenerateOptions configures Generate.
+type GenerateOptions struct {
+	// Endpoint is the Hydra Admin API URL.
+	Endpoint string
+	// JWKSNames lists the JSON Web Key Sets to emit, since the Admin API has no way to list them.
+	JWKSNames []string
+}
+
+var invalidResourceNameChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// generatedJSONAttributes hold JSON documents, which are emitted as jsonencode calls the way they are usually configured.
+var generatedJSONAttributes = []string{"metadata"}
+
+// Generate lists existing OAuth2 clients and the given JWK sets through the Admin API
+// and writes matching resources together with import blocks to w.
+func Generate(ctx context.Context, w io.Writer, opts GenerateOptions) error {
+	meta, err := configureGenerator(ctx, opts.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	clients, err := listOAuth2Clients(ctx, meta, "", "")
+	if err != nil {
+		return err
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	names := make(map[string]bool)
+
+	clientResource := resourceOAuth2Client()
+	for i := range clients {
+		data := clientResource.Data(nil)
+		if err := dataFromClient(data, &clients[i]); err != nil {
+			return err
+		}
+
+		name := uniqueResourceName(names, clients[i].GetClientName(), clients[i].GetClientId())
+		appendGeneratedResource(body, "hydra_oauth2_client", name, data.Id(), clientResource.Schema, data)
+	}
+
+	jwksResource := resourceJWKS()
+	for _, setName := range opts.JWKSNames {
+		// Keys are left in state only, so that private key material never ends up in configuration.
+		data := jwksResource.Data(nil)
+		data.SetId(setName)
+		data.Set("name", setName)
+
+		name := uniqueResourceName(names, setName, setName)
+		appendGeneratedResource(body, "hydra_jwks", name, setName, map[string]*schema.Schema{
+			"name": jwksResource.Schema["name"],
+		}, data)
+	}
+
+	_, err = file.WriteTo(w)
+	return err
+}
+
+func configureGenerator(ctx context.Context, endpoint string) (*ClientConfig, error) {
+	raw := map[string]interface{}{
+		"endpoint": endpoint,
+	}
+	if authentication := generatorAuthentication(os.Getenv); len(authentication) > 0 {
+		raw["authentication"] = []interface{}{authentication}
+	}
+
+	p := New()
+	for _, d := range p.Configure(ctx, terraform.NewResourceConfigRaw(raw)) {
+		if d.Severity == diag.Error {
+			return nil, fmt.Errorf("failed to configure provider: %s", d.Summary)
+		}
+	}
+
+	return p.Meta().(*ClientConfig), nil
+}
+
+// generatorAuthentication configures authentication from the environment variables the provider reads its defaults from.
+// Basic, HTTP header and OAuth2 authentication exclude each other in this order, a TLS client certificate can be combined with any of them.
+func generatorAuthentication(getenv func(string) string) map[string]interface{} {
+	authentication := make(map[string]interface{})
+
+	if username := getenv("HYDRA_ADMIN_BASIC_AUTH_USERNAME"); username != "" {
+		authentication["basic"] = []interface{}{map[string]interface{}{
+			"username": username,
+			"password": getenv("HYDRA_ADMIN_BASIC_AUTH_PASSWORD"),
+		}}
+	} else if value := getenv("HYDRA_ADMIN_AUTH_HTTP_HEADER_VALUE"); value != "" {
+		name := getenv("HYDRA_ADMIN_AUTH_HTTP_HEADER_NAME")
+		if name == "" {
+			name = "Authorization"
+		}
+		authentication["http_header"] = []interface{}{map[string]interface{}{
+			"name":  name,
+			"value": value,
+		}}
+	} else if clientID := getenv("HYDRA_ADMIN_OAUTH2_CLIENT_ID"); clientID != "" {
+		authentication["oauth2"] = []interface{}{map[string]interface{}{
+			"token_endpoint": getenv("HYDRA_ADMIN_OAUTH2_TOKEN_ENDPOINT"),
+			"client_id":      clientID,
+			"client_secret":  getenv("HYDRA_ADMIN_OAUTH2_CLIENT_SECRET"),
+			"audience":       envList(getenv("HYDRA_ADMIN_OAUTH2_AUDIENCE")),
+			"scopes":         envList(getenv("HYDRA_ADMIN_OAUTH2_SCOPES")),
+		}}
+	}
+
+	if certificate := getenv("HYDRA_ADMIN_TLS_AUTH_CERT_DATA"); certificate != "" {
+		insecureSkipVerify, _ := strconv.ParseBool(getenv("HYDRA_ADMIN_TLS_AUTH_INSECURE"))
+		authentication["tls"] = []interface{}{map[string]interface{}{
+			"certificate":          certificate,
+			"key":                  getenv("HYDRA_ADMIN_TLS_AUTH_KEY_DATA"),
+			"insecure_skip_verify": insecureSkipVerify,
+		}}
+	}
+
+	return authentication
+}
+
+// envList splits a comma or space separated environment variable.
+func envList(value string) []interface{} {
+	var items []interface{}
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		items = append(items, item)
+	}
+	return items
+}
+
+func uniqueResourceName(names map[string]bool, candidates ...string) string {
+	name := ""
+	for _, candidate := range candidates {
+		name = strings.Trim(invalidResourceNameChars.ReplaceAllString(strings.ToLower(candidate), "_"), "_")
+		if name != "" {
+			break
+		}
+	}
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "r_" + name
+	}
+
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[unique] = true
+
+	return unique
+}
+
+func appendGeneratedResource(body *hclwrite.Body, resourceType, name, id string, s map[string]*schema.Schema, data *schema.ResourceData) {
+	importBlock := body.AppendNewBlock("import", nil)
+	importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: name},
+	})
+	importBlock.Body().SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+
+	values := make(map[string]interface{}, len(s))
+	for k, v := range s {
+		// Hydra never returns secrets, so there is nothing meaningful to emit for them.
+		if v.Sensitive {
+			continue
+		}
+		values[k] = data.Get(k)
+	}
+
+	resourceBlock := body.AppendNewBlock("resource", []string{resourceType, name})
+	appendGeneratedBody(resourceBlock.Body(), s, values)
+	body.AppendNewline()
+}
+
+func appendGeneratedBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !s[k].Optional && !s[k].Required {
+			continue
+		}
+
+		value := values[k]
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		if isEmptyValue(value) {
+			continue
+		}
+
+		if elem, ok := s[k].Elem.(*schema.Resource); ok {
+			for _, item := range value.([]interface{}) {
+				block := body.AppendNewBlock(k, nil)
+				appendGeneratedBody(block.Body(), elem.Schema, item.(map[string]interface{}))
+			}
+			continue
+		}
+
+		if document, ok := value.(string); ok && slices.Contains(generatedJSONAttributes, k) {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(document), &decoded); err == nil {
+				body.SetAttributeRaw(k, hclwrite.TokensForFunctionCall("jsonencode", hclwrite.TokensForValue(generatedValueToCty(decoded))))
+				continue
+			}
+		}
+
+		body.SetAttributeValue(k, generatedValueToCty(value))
+	}
+}
+
+func generatedValueToCty(value interface{}) cty.Value {
+	switch v := value.(type) {
+	case string:
+		return cty.StringVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case *schema.Set:
+		return generatedValueToCty(v.List())
+	case []interface{}:
+		items := make([]cty.Value, len(v))
+		for i, item := range v {
+			items[i] = generatedValueToCty(item)
+		}
+		return cty.TupleVal(items)
+	case map[string]interface{}:
+		attrs := make(map[string]cty.Value, len(v))
+		for k, item := range v {
+			attrs[k] = generatedValueToCty(item)
+		}
+		return cty.ObjectVal(attrs)
+	}
+	return cty.NullVal(cty.DynamicPseudoType)
+}
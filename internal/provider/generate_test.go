@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Setenv("HYDRA_ADMIN_AUTH_HTTP_HEADER_VALUE", "Bearer admin")
+
+	hydraClientStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "Bearer admin", req.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Query().Get("page_token") {
+		case "":
+			w.Header().Set("Link", `</admin/clients?page_size=100&page_token=next>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"client_id": "a1", "client_name": "My App", "redirect_uris": ["https://example.com/callback"], "metadata": {"team": "a"}, "jwks": {}}]`))
+		default:
+			_, _ = w.Write([]byte(`[{"client_id": "b2", "client_name": "My App", "grant_types": ["client_credentials"], "jwks": {}}]`))
+		}
+	}))
+	defer hydraClientStub.Close()
+
+	var out bytes.Buffer
+	err := Generate(context.Background(), &out, GenerateOptions{
+		Endpoint:  hydraClientStub.URL,
+		JWKSNames: []string{"hydra.openid.id-token"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, `import {
+  to = hydra_oauth2_client.my_app
+  id = "a1"
+}
+
+resource "hydra_oauth2_client" "my_app" {
+  client_id   = "a1"
+  client_name = "My App"
+  metadata = jsonencode({
+    team = "a"
+  })
+  redirect_uris = ["https://example.com/callback"]
+}
+
+import {
+  to = hydra_oauth2_client.my_app_2
+  id = "b2"
+}
+
+resource "hydra_oauth2_client" "my_app_2" {
+  client_id   = "b2"
+  client_name = "My App"
+  grant_types = ["client_credentials"]
+}
+
+import {
+  to = hydra_jwks.hydra_openid_id_token
+  id = "hydra.openid.id-token"
+}
+
+resource "hydra_jwks" "hydra_openid_id_token" {
+  name = "hydra.openid.id-token"
+}
+
+`, out.String())
+}
+
+func TestGeneratorAuthentication(t *testing.T) {
+	getenv := func(env map[string]string) func(string) string {
+		return func(key string) string { return env[key] }
+	}
+
+	require.Empty(t, generatorAuthentication(getenv(nil)))
+
+	require.Equal(t, map[string]interface{}{
+		"oauth2": []interface{}{map[string]interface{}{
+			"token_endpoint": "https://auth.example.com/oauth2/token",
+			"client_id":      "terraform",
+			"client_secret":  "secret",
+			"audience":       []interface{}{"https://hydra.example.com"},
+			"scopes":         []interface{}{"clients", "keys"},
+		}},
+		"tls": []interface{}{map[string]interface{}{
+			"certificate":          "certificate",
+			"key":                  "key",
+			"insecure_skip_verify": true,
+		}},
+	}, generatorAuthentication(getenv(map[string]string{
+		"HYDRA_ADMIN_OAUTH2_TOKEN_ENDPOINT": "https://auth.example.com/oauth2/token",
+		"HYDRA_ADMIN_OAUTH2_CLIENT_ID":      "terraform",
+		"HYDRA_ADMIN_OAUTH2_CLIENT_SECRET":  "secret",
+		"HYDRA_ADMIN_OAUTH2_AUDIENCE":       "https://hydra.example.com",
+		"HYDRA_ADMIN_OAUTH2_SCOPES":         "clients, keys",
+		"HYDRA_ADMIN_TLS_AUTH_CERT_DATA":    "certificate",
+		"HYDRA_ADMIN_TLS_AUTH_KEY_DATA":     "key",
+		"HYDRA_ADMIN_TLS_AUTH_INSECURE":     "true",
+	})))
+
+	// Basic authentication takes precedence, like in the provider configuration.
+	authentication := generatorAuthentication(getenv(map[string]string{
+		"HYDRA_ADMIN_BASIC_AUTH_USERNAME":    "admin",
+		"HYDRA_ADMIN_AUTH_HTTP_HEADER_VALUE": "Bearer admin",
+		"HYDRA_ADMIN_OAUTH2_CLIENT_ID":       "terraform",
+	}))
+	require.Contains(t, authentication, "basic")
+	require.Len(t, authentication, 1)
+}
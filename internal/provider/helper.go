@@ -3,8 +3,11 @@ package provider
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -13,6 +16,9 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+// listPageSize is the page size used when traversing Hydra list endpoints.
+const listPageSize = 100
+
 func ptr[T any](v T) *T {
 	return &v
 }
@@ -25,6 +31,27 @@ func strSlice(items []interface{}) []string {
 	return result
 }
 
+// computedSchema copies a resource schema into a data source schema in which every attribute is read-only.
+func computedSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	result := make(map[string]*schema.Schema, len(s))
+	for key, attr := range s {
+		computed := &schema.Schema{
+			Type:        attr.Type,
+			Computed:    true,
+			Sensitive:   attr.Sensitive,
+			Description: attr.Description,
+		}
+		switch elem := attr.Elem.(type) {
+		case *schema.Resource:
+			computed.Elem = &schema.Resource{Schema: computedSchema(elem.Schema)}
+		case *schema.Schema:
+			computed.Elem = &schema.Schema{Type: elem.Type}
+		}
+		result[key] = computed
+	}
+	return result
+}
+
 // diffSuppressMatchingDurationStrings compares two string time durations and returns true if they are equal, regardless of formatting.
 func diffSuppressMatchingDurationStrings(k, old, new string, d *schema.ResourceData) bool {
 	oldDuration, err := time.ParseDuration(old)
@@ -40,6 +67,73 @@ func diffSuppressMatchingDurationStrings(k, old, new string, d *schema.ResourceD
 	return oldDuration == newDuration
 }
 
+// diffSuppressEquivalentRFC3339 ignores differences in the formatting of timestamps, such as time zones and fractional seconds.
+func diffSuppressEquivalentRFC3339(k, old, new string, d *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+
+	return oldTime.Equal(newTime)
+}
+
+// diffSuppressEquivalentJSON compares two JSON documents and returns true if they are semantically equal, regardless of formatting and key order.
+func diffSuppressEquivalentJSON(k, old, new string, d *schema.ResourceData) bool {
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+// normalizeJSON returns the compact form of a JSON document with sorted object keys, the same form Hydra's metadata is read in.
+// Invalid documents are returned unchanged to be reported by validation.
+func normalizeJSON(val interface{}) string {
+	document := val.(string)
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return document
+	}
+
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return document
+	}
+	return string(normalized)
+}
+
+// isEmptyValue reports whether a decoded JSON or schema value is nil, the zero value of its type, or an empty list or map.
+// Hydra omits such values from clients, so they can't be told apart from values which are left out.
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
 // retryThrottledHydraAction executes the fn function and if backOff is set, retries the function if the request is throttled.
 func retryThrottledHydraAction(fn func() (*http.Response, error), backOff backoff.BackOff) error {
 	if backOff == nil || reflect.ValueOf(backOff).IsNil() {
@@ -65,6 +159,41 @@ func retryThrottledHydraAction(fn func() (*http.Response, error), backOff backof
 	return backoff.Retry(retryAction, backOff)
 }
 
+// nextPageToken extracts the page token of the rel="next" link from the Link header returned by Hydra list endpoints.
+// An empty string is returned when there are no more pages.
+func nextPageToken(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	for _, header := range resp.Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			if len(parts) < 2 {
+				continue
+			}
+
+			isNext := false
+			for _, param := range parts[1:] {
+				if strings.TrimSpace(param) == `rel="next"` {
+					isNext = true
+				}
+			}
+			if !isNext {
+				continue
+			}
+
+			linkURL, err := url.Parse(strings.Trim(strings.TrimSpace(parts[0]), "<>"))
+			if err != nil {
+				return ""
+			}
+			return linkURL.Query().Get("page_token")
+		}
+	}
+
+	return ""
+}
+
 func validateDuration(val interface{}, key string) (ws []string, errors []error) {
 	v, ok := val.(string)
 	if !ok {
@@ -78,6 +207,74 @@ func validateDuration(val interface{}, key string) (ws []string, errors []error)
 	return
 }
 
+// validateURI returns a function which checks that a value is an absolute URL without a fragment.
+// Plain http is only accepted for loopback hosts, and schemes other than http(s) only if allowCustomScheme is set, e.g. for redirects to native apps.
+func validateURI(allowCustomScheme bool) schema.SchemaValidateFunc {
+	return func(val interface{}, key string) (ws []string, errors []error) {
+		v, ok := val.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+			return
+		}
+		if v == "" {
+			return
+		}
+
+		u, err := url.Parse(v)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%q must be a valid URL: %s", key, err))
+			return
+		}
+		if !u.IsAbs() {
+			errors = append(errors, fmt.Errorf("%q must be an absolute URL, got %q", key, v))
+			return
+		}
+		if u.Fragment != "" || strings.Contains(v, "#") {
+			errors = append(errors, fmt.Errorf("%q must not contain a fragment, got %q", key, v))
+		}
+
+		switch u.Scheme {
+		case "https":
+		case "http":
+			if !isLoopbackHost(u.Hostname()) {
+				errors = append(errors, fmt.Errorf("%q must use https unless it points to a loopback address, got %q", key, v))
+			}
+		default:
+			if !allowCustomScheme {
+				errors = append(errors, fmt.Errorf("%q must use https, got %q", key, v))
+			}
+			return
+		}
+		if u.Host == "" {
+			errors = append(errors, fmt.Errorf("%q must contain a host, got %q", key, v))
+		}
+		return
+	}
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isAllowedHost checks whether host matches one of the patterns, either exactly or as a subdomain of a `*.` wildcard pattern.
+func isAllowedHost(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if host == pattern {
+			return true
+		}
+		if strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
 // checkResourceAttrJSON compares the JSON structure by unmarshalling both the actual and expected values into Go maps and comparing those, rather than comparing the raw JSON strings.
 func checkResourceAttrJSON(resourceName, attributeName, expectedJSON string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
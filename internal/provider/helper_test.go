@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextPageToken(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	require.Equal(t, "", nextPageToken(resp))
+
+	resp.Header.Set("Link", `</admin/clients?page_size=100&page_token=first>; rel="first"`)
+	require.Equal(t, "", nextPageToken(resp))
+
+	resp.Header.Set("Link", `</admin/clients?page_size=100&page_token=first>; rel="first",</admin/clients?page_size=100&page_token=eyJwayI6IjIifQ>; rel="next"`)
+	require.Equal(t, "eyJwayI6IjIifQ", nextPageToken(resp))
+
+	require.Equal(t, "", nextPageToken(nil))
+}
+
+func TestValidateURI(t *testing.T) {
+	for uri, valid := range map[string]bool{
+		"https://example.com/callback":     true,
+		"http://localhost:8080/callback":   true,
+		"http://127.0.0.1/callback":        true,
+		"http://[::1]:8080/callback":       true,
+		"com.example.app:/oauth2/callback": true,
+		"http://example.com/callback":      false,
+		"https://example.com/callback#x":   false,
+		"/callback":                        false,
+		"https:///callback":                false,
+	} {
+		_, errs := validateURI(true)(uri, "redirect_uris")
+		require.Equal(t, valid, len(errs) == 0, "%s: %v", uri, errs)
+	}
+
+	_, errs := validateURI(false)("com.example.app:/oauth2/callback", "jwks_uri")
+	require.NotEmpty(t, errs)
+}
+
+func TestIsAllowedHost(t *testing.T) {
+	patterns := []string{"example.com", "*.example.org"}
+
+	require.True(t, isAllowedHost("example.com", patterns))
+	require.True(t, isAllowedHost("EXAMPLE.com", patterns))
+	require.True(t, isAllowedHost("app.example.org", patterns))
+	require.False(t, isAllowedHost("example.org", patterns))
+	require.False(t, isAllowedHost("app.example.com", patterns))
+	require.False(t, isAllowedHost("evilexample.org", patterns))
+}
+
+func TestDiffSuppressEquivalentJSON(t *testing.T) {
+	require.True(t, diffSuppressEquivalentJSON("", `{"a": 1, "b": {"c": true}}`, `{"b":{"c":true},"a":1}`, nil))
+	require.False(t, diffSuppressEquivalentJSON("", `{"a": 1}`, `{"a": "1"}`, nil))
+	require.False(t, diffSuppressEquivalentJSON("", ``, `{}`, nil))
+}
+
+func TestDiffSuppressEquivalentRFC3339(t *testing.T) {
+	require.True(t, diffSuppressEquivalentRFC3339("", "2030-01-01T00:00:00Z", "2030-01-01T01:00:00.000+01:00", nil))
+	require.False(t, diffSuppressEquivalentRFC3339("", "2030-01-01T00:00:00Z", "2030-01-01T00:00:01Z", nil))
+	require.False(t, diffSuppressEquivalentRFC3339("", "", "2030-01-01T00:00:00Z", nil))
+}
+
+func TestNormalizeJSON(t *testing.T) {
+	require.Equal(t, `{"a":1,"b":{"c":true}}`, normalizeJSON(`{
+  "b": {"c": true},
+  "a": 1
+}`))
+	require.Equal(t, `not json`, normalizeJSON(`not json`))
+}
+
+func TestIsEmptyValue(t *testing.T) {
+	for _, value := range []interface{}{nil, "", false, 0, float64(0), []interface{}{}, map[string]interface{}{}} {
+		require.True(t, isEmptyValue(value), "%#v", value)
+	}
+	for _, value := range []interface{}{"a", true, 1, 0.5, []interface{}{""}, map[string]interface{}{"a": nil}} {
+		require.False(t, isEmptyValue(value), "%#v", value)
+	}
+}
+
+// newHydraStub serves handler as Hydra's Admin API for the duration of the test and configures the provider against it.
+func newHydraStub(t *testing.T, handler http.Handler) *ClientConfig {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return configureTestProvider(t, map[string]interface{}{"endpoint": server.URL})
+}
+
+// configureTestProvider configures the provider with the raw provider configuration and returns its meta.
+func configureTestProvider(t *testing.T, raw map[string]interface{}) *ClientConfig {
+	t.Helper()
+
+	p := New()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(raw))
+	require.False(t, diags.HasError(), "%v", diags)
+
+	return p.Meta().(*ClientConfig)
+}
@@ -0,0 +1,392 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+// parsePublicKeyPEM parses a PKIX or PKCS #1 public key. Private keys are rejected, so they can't be handed out by accident.
+func parsePublicKeyPEM(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("PEM block %q contains a private key, only public keys are accepted", block.Type)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q, expected %q", block.Type, "PUBLIC KEY")
+	}
+}
+
+// parseCertificateChainPEM parses a chain of certificates, starting with the certificate of the key.
+func parseCertificateChainPEM(data string) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unsupported PEM block %q, expected %q", block.Type, "CERTIFICATE")
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return certificates, nil
+}
+
+// parsePrivateKeyPEM parses a PKCS #8, PKCS #1 or SEC 1 private key.
+func parsePrivateKeyPEM(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q, expected %q", block.Type, "PRIVATE KEY")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func validatePublicKeyPEM(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+
+	publicKey, err := parsePublicKeyPEM(v)
+	if err == nil {
+		err = setJWKPublicKey(&hydra.JsonWebKey{}, publicKey)
+	}
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a PEM encoded public key: %s", key, err))
+	}
+	return
+}
+
+func validateCertificatePEM(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+
+	certificates, err := parseCertificateChainPEM(v)
+	if err == nil {
+		err = setJWKPublicKey(&hydra.JsonWebKey{}, certificates[0].PublicKey)
+	}
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a PEM encoded certificate chain: %s", key, err))
+	}
+	return
+}
+
+func validatePrivateKeyPEM(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+
+	privateKey, err := parsePrivateKeyPEM(v)
+	if err == nil {
+		_, err = jwsAlgorithm(privateKey.Public(), "")
+	}
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a PEM encoded private key: %s", key, err))
+	}
+	return
+}
+
+// setJWKFromPEM fills the public members of jwk from a PEM encoded public key or certificate chain.
+// The key id defaults to the RFC 7638 thumbprint of the key.
+func setJWKFromPEM(jwk *hydra.JsonWebKey, publicKeyPEM, certificatePEM string) error {
+	var publicKey crypto.PublicKey
+	switch {
+	case certificatePEM != "":
+		certificates, err := parseCertificateChainPEM(certificatePEM)
+		if err != nil {
+			return err
+		}
+		publicKey = certificates[0].PublicKey
+		setJWKCertificateChain(jwk, certificates)
+	case publicKeyPEM != "":
+		var err error
+		publicKey, err = parsePublicKeyPEM(publicKeyPEM)
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if err := setJWKPublicKey(jwk, publicKey); err != nil {
+		return err
+	}
+
+	return setJWKDefaultKid(jwk)
+}
+
+// setJWKFromPrivateKeyPEM fills the public and private members of jwk from a PEM encoded private key,
+// and x5c from the certificate chain of the key if one is given. The key id defaults to the RFC 7638 thumbprint of the key.
+func setJWKFromPrivateKeyPEM(jwk *hydra.JsonWebKey, privateKeyPEM, certificateChainPEM string) error {
+	privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return err
+	}
+
+	if certificateChainPEM != "" {
+		certificates, err := parseCertificateChainPEM(certificateChainPEM)
+		if err != nil {
+			return err
+		}
+		if publicKey, ok := privateKey.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !publicKey.Equal(certificates[0].PublicKey) {
+			return errors.New("the first certificate of the chain doesn't belong to the private key")
+		}
+		setJWKCertificateChain(jwk, certificates)
+	}
+
+	if err := setJWKPublicKey(jwk, privateKey.Public()); err != nil {
+		return err
+	}
+	if err := setJWKPrivateKey(jwk, privateKey); err != nil {
+		return err
+	}
+
+	return setJWKDefaultKid(jwk)
+}
+
+func setJWKCertificateChain(jwk *hydra.JsonWebKey, certificates []*x509.Certificate) {
+	jwk.X5c = make([]string, len(certificates))
+	for i, certificate := range certificates {
+		jwk.X5c[i] = base64.StdEncoding.EncodeToString(certificate.Raw)
+	}
+}
+
+func setJWKDefaultKid(jwk *hydra.JsonWebKey) error {
+	if jwk.Kid != "" {
+		return nil
+	}
+
+	thumbprint, err := jwkThumbprint(jwk)
+	if err != nil {
+		return err
+	}
+	jwk.Kid = thumbprint
+	return nil
+}
+
+// setJWKPublicKey sets the key type and public members of jwk.
+func setJWKPublicKey(jwk *hydra.JsonWebKey, publicKey crypto.PublicKey) error {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = ptr(base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+		jwk.E = ptr(base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()))
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() && key.Curve != elliptic.P384() && key.Curve != elliptic.P521() {
+			return fmt.Errorf("unsupported elliptic curve %q", key.Curve.Params().Name)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = ptr(key.Curve.Params().Name)
+		jwk.X = ptr(base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))))
+		jwk.Y = ptr(base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = ptr("Ed25519")
+		jwk.X = ptr(base64.RawURLEncoding.EncodeToString(key))
+	default:
+		return fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+	return nil
+}
+
+// jwkPublicKey is the inverse of setJWKPublicKey.
+func jwkPublicKey(jwk *hydra.JsonWebKey) (crypto.PublicKey, error) {
+	decode := func(member, value string) ([]byte, error) {
+		b, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q must be base64url encoded: %w", member, err)
+		}
+		return b, nil
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode("n", jwk.GetN())
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode("e", jwk.GetE())
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.GetCrv() {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported elliptic curve %q", jwk.GetCrv())
+		}
+		x, err := decode("x", jwk.GetX())
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode("y", jwk.GetY())
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if _, err := publicKey.ECDH(); err != nil {
+			return nil, err
+		}
+		return publicKey, nil
+	case "OKP":
+		x, err := decode("x", jwk.GetX())
+		if err != nil {
+			return nil, err
+		}
+		switch jwk.GetCrv() {
+		case "Ed25519":
+			if len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid Ed25519 public key size %d", len(x))
+			}
+			return ed25519.PublicKey(x), nil
+		case "X25519":
+			return ecdh.X25519().NewPublicKey(x)
+		}
+		return nil, fmt.Errorf("unsupported curve %q", jwk.GetCrv())
+	}
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+// jwkPublicKeyPEM encodes the public key of jwk as a PKIX public key.
+func jwkPublicKeyPEM(jwk *hydra.JsonWebKey) (string, error) {
+	publicKey, err := jwkPublicKey(jwk)
+	if err != nil {
+		return "", err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// publicJWK returns a copy of jwk without its private members. ok is false for symmetric keys, which have no public part.
+func publicJWK(jwk hydra.JsonWebKey) (hydra.JsonWebKey, bool) {
+	if jwk.Kty == "oct" {
+		return hydra.JsonWebKey{}, false
+	}
+
+	jwk.D, jwk.P, jwk.Q, jwk.Dp, jwk.Dq, jwk.Qi, jwk.K = nil, nil, nil, nil, nil, nil, nil
+	return jwk, true
+}
+
+// setJWKPrivateKey sets the private members of jwk, following RFC 7518 section 6.2.2 and 6.3.2 and RFC 8037 section 2.
+func setJWKPrivateKey(jwk *hydra.JsonWebKey, privateKey crypto.Signer) error {
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if len(key.Primes) != 2 {
+			return fmt.Errorf("unsupported RSA key with %d primes", len(key.Primes))
+		}
+		key.Precompute()
+		jwk.D = ptr(base64.RawURLEncoding.EncodeToString(key.D.Bytes()))
+		jwk.P = ptr(base64.RawURLEncoding.EncodeToString(key.Primes[0].Bytes()))
+		jwk.Q = ptr(base64.RawURLEncoding.EncodeToString(key.Primes[1].Bytes()))
+		jwk.Dp = ptr(base64.RawURLEncoding.EncodeToString(key.Precomputed.Dp.Bytes()))
+		jwk.Dq = ptr(base64.RawURLEncoding.EncodeToString(key.Precomputed.Dq.Bytes()))
+		jwk.Qi = ptr(base64.RawURLEncoding.EncodeToString(key.Precomputed.Qinv.Bytes()))
+	case *ecdsa.PrivateKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.D = ptr(base64.RawURLEncoding.EncodeToString(key.D.FillBytes(make([]byte, size))))
+	case ed25519.PrivateKey:
+		jwk.D = ptr(base64.RawURLEncoding.EncodeToString(key.Seed()))
+	default:
+		return fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+	return nil
+}
+
+// jwkThumbprint computes the RFC 7638 SHA-256 thumbprint of jwk from its required public members.
+func jwkThumbprint(jwk *hydra.JsonWebKey) (string, error) {
+	var members map[string]string
+	switch jwk.Kty {
+	case "RSA":
+		members = map[string]string{"e": jwk.GetE(), "kty": jwk.Kty, "n": jwk.GetN()}
+	case "EC":
+		members = map[string]string{"crv": jwk.GetCrv(), "kty": jwk.Kty, "x": jwk.GetX(), "y": jwk.GetY()}
+	case "OKP":
+		members = map[string]string{"crv": jwk.GetCrv(), "kty": jwk.Kty, "x": jwk.GetX()}
+	case "oct":
+		members = map[string]string{"k": jwk.GetK(), "kty": jwk.Kty}
+	default:
+		return "", fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+
+	// encoding/json sorts map keys and emits no whitespace, which is the canonical form RFC 7638 requires.
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
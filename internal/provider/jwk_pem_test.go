@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKThumbprint(t *testing.T) {
+	// Example from RFC 7638, section 3.1.
+	thumbprint, err := jwkThumbprint(&hydra.JsonWebKey{
+		Kty: "RSA",
+		Kid: "2011-04-29",
+		Alg: "RS256",
+		N:   ptr("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"),
+		E:   ptr("AQAB"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint)
+}
+
+func TestSetJWKFromPEM_publicKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	jwk := &hydra.JsonWebKey{Alg: "RS256", Use: "sig"}
+	require.NoError(t, setJWKFromPEM(jwk, publicKeyPEM, ""))
+	require.Equal(t, "RSA", jwk.Kty)
+	require.Equal(t, "AQAB", jwk.GetE())
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()), jwk.GetN())
+
+	thumbprint, err := jwkThumbprint(jwk)
+	require.NoError(t, err)
+	require.Equal(t, thumbprint, jwk.Kid)
+
+	_, errs := validatePublicKeyPEM(publicKeyPEM, "public_key_pem")
+	require.Empty(t, errs)
+
+	privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+	_, errs = validatePublicKeyPEM(privateKeyPEM, "public_key_pem")
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "only public keys are accepted")
+}
+
+func TestSetJWKFromPEM_certificate(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "partner"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	certificatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	jwk := &hydra.JsonWebKey{Alg: "ES256", Kid: "partner", Use: "sig"}
+	require.NoError(t, setJWKFromPEM(jwk, "", certificatePEM))
+	require.Equal(t, "EC", jwk.Kty)
+	require.Equal(t, "P-256", jwk.GetCrv())
+	require.Len(t, jwk.GetX(), 43)
+	require.Len(t, jwk.GetY(), 43)
+	require.Equal(t, []string{base64.StdEncoding.EncodeToString(der)}, jwk.X5c)
+	require.Equal(t, "partner", jwk.Kid)
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	ed25519Key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, publicKey := range []interface{ Equal(crypto.PublicKey) bool }{&rsaKey.PublicKey, &ecKey.PublicKey, ed25519Key} {
+		jwk := &hydra.JsonWebKey{}
+		require.NoError(t, setJWKPublicKey(jwk, publicKey))
+
+		restored, err := jwkPublicKey(jwk)
+		require.NoError(t, err)
+		require.True(t, publicKey.Equal(restored), "%T", publicKey)
+
+		publicKeyPEM, err := jwkPublicKeyPEM(jwk)
+		require.NoError(t, err)
+		_, errs := validatePublicKeyPEM(publicKeyPEM, "public_key_pem")
+		require.Empty(t, errs)
+	}
+
+	_, err = jwkPublicKey(&hydra.JsonWebKey{Kty: "oct", K: ptr("c2VjcmV0")})
+	require.ErrorContains(t, err, `unsupported key type "oct"`)
+}
+
+func TestSetJWKFromPrivateKeyPEM(t *testing.T) {
+	decode := func(t *testing.T, member *string) *big.Int {
+		b, err := base64.RawURLEncoding.DecodeString(*member)
+		require.NoError(t, err)
+		return new(big.Int).SetBytes(b)
+	}
+
+	t.Run("case=RSA", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+
+		jwk := &hydra.JsonWebKey{Alg: "RS256", Use: "sig"}
+		require.NoError(t, setJWKFromPrivateKeyPEM(jwk, privateKeyPEM, ""))
+		require.NoError(t, validateJWK(jwk))
+
+		restored := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: decode(t, jwk.N), E: int(decode(t, jwk.E).Int64())},
+			D:         decode(t, jwk.D),
+			Primes:    []*big.Int{decode(t, jwk.P), decode(t, jwk.Q)},
+		}
+		require.NoError(t, restored.Validate())
+		require.True(t, restored.Equal(privateKey))
+		require.Equal(t, privateKey.Precomputed.Qinv, decode(t, jwk.Qi))
+
+		thumbprint, err := jwkThumbprint(jwk)
+		require.NoError(t, err)
+		require.Equal(t, thumbprint, jwk.Kid)
+	})
+
+	t.Run("case=EC with certificate chain", func(t *testing.T) {
+		privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+		template := &x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+		certificate, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+		require.NoError(t, err)
+		certificatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}))
+
+		jwk := &hydra.JsonWebKey{Alg: "ES384", Kid: "signing", Use: "sig"}
+		require.NoError(t, setJWKFromPrivateKeyPEM(jwk, privateKeyPEM, certificatePEM))
+		require.NoError(t, validateJWK(jwk))
+		require.Equal(t, "P-384", jwk.GetCrv())
+		require.Equal(t, privateKey.D, decode(t, jwk.D))
+		require.Len(t, jwk.GetD(), 64)
+		require.Equal(t, []string{base64.StdEncoding.EncodeToString(certificate)}, jwk.X5c)
+		require.Equal(t, "signing", jwk.Kid)
+
+		otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+		der, err = x509.MarshalECPrivateKey(otherKey)
+		require.NoError(t, err)
+		otherKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+		require.ErrorContains(t, setJWKFromPrivateKeyPEM(&hydra.JsonWebKey{}, otherKeyPEM, certificatePEM), "doesn't belong to the private key")
+	})
+
+	t.Run("case=Ed25519", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+
+		jwk := &hydra.JsonWebKey{Alg: "EdDSA", Use: "sig"}
+		require.NoError(t, setJWKFromPrivateKeyPEM(jwk, privateKeyPEM, ""))
+		require.NoError(t, validateJWK(jwk))
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(publicKey), jwk.GetX())
+		require.Equal(t, base64.RawURLEncoding.EncodeToString(privateKey.Seed()), jwk.GetD())
+	})
+}
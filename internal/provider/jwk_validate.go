@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+// minRSAKeySize is the smallest RSA modulus in bits accepted for keys, following RFC 7518 section 3.3 and 4.2.
+const minRSAKeySize = 2048
+
+// jwkAlgorithm describes which keys can be used with an algorithm of the JOSE registry.
+type jwkAlgorithm struct {
+	use    string
+	kty    []string
+	curves []string
+}
+
+var (
+	ecCurves   = []string{"P-256", "P-384", "P-521"}
+	okpCurves  = []string{"Ed25519", "Ed448", "X25519", "X448"}
+	ecdhKeys   = []string{"EC", "OKP"}
+	ecdhCurves = []string{"P-256", "P-384", "P-521", "X25519", "X448"}
+)
+
+// jwkAlgorithms are the algorithms of RFC 7518 and RFC 8037 which can be used with a JWK.
+var jwkAlgorithms = map[string]jwkAlgorithm{
+	"HS256":          {use: "sig", kty: []string{"oct"}},
+	"HS384":          {use: "sig", kty: []string{"oct"}},
+	"HS512":          {use: "sig", kty: []string{"oct"}},
+	"RS256":          {use: "sig", kty: []string{"RSA"}},
+	"RS384":          {use: "sig", kty: []string{"RSA"}},
+	"RS512":          {use: "sig", kty: []string{"RSA"}},
+	"PS256":          {use: "sig", kty: []string{"RSA"}},
+	"PS384":          {use: "sig", kty: []string{"RSA"}},
+	"PS512":          {use: "sig", kty: []string{"RSA"}},
+	"ES256":          {use: "sig", kty: []string{"EC"}, curves: []string{"P-256"}},
+	"ES384":          {use: "sig", kty: []string{"EC"}, curves: []string{"P-384"}},
+	"ES512":          {use: "sig", kty: []string{"EC"}, curves: []string{"P-521"}},
+	"EdDSA":          {use: "sig", kty: []string{"OKP"}, curves: []string{"Ed25519", "Ed448"}},
+	"RSA1_5":         {use: "enc", kty: []string{"RSA"}},
+	"RSA-OAEP":       {use: "enc", kty: []string{"RSA"}},
+	"RSA-OAEP-256":   {use: "enc", kty: []string{"RSA"}},
+	"A128KW":         {use: "enc", kty: []string{"oct"}},
+	"A192KW":         {use: "enc", kty: []string{"oct"}},
+	"A256KW":         {use: "enc", kty: []string{"oct"}},
+	"A128GCMKW":      {use: "enc", kty: []string{"oct"}},
+	"A192GCMKW":      {use: "enc", kty: []string{"oct"}},
+	"A256GCMKW":      {use: "enc", kty: []string{"oct"}},
+	"dir":            {use: "enc", kty: []string{"oct"}},
+	"ECDH-ES":        {use: "enc", kty: ecdhKeys, curves: ecdhCurves},
+	"ECDH-ES+A128KW": {use: "enc", kty: ecdhKeys, curves: ecdhCurves},
+	"ECDH-ES+A192KW": {use: "enc", kty: ecdhKeys, curves: ecdhCurves},
+	"ECDH-ES+A256KW": {use: "enc", kty: ecdhKeys, curves: ecdhCurves},
+}
+
+// jwkMembers are the required and optional key members by key type, following RFC 7518 section 6 and RFC 8037 section 2.
+var jwkMembers = map[string]struct {
+	required []string
+	optional []string
+}{
+	"RSA": {required: []string{"n", "e"}, optional: []string{"d", "p", "q", "dp", "dq", "qi"}},
+	"EC":  {required: []string{"crv", "x", "y"}, optional: []string{"d"}},
+	"OKP": {required: []string{"crv", "x"}, optional: []string{"d"}},
+	"oct": {required: []string{"k"}},
+}
+
+// jwkGeneratorAlgorithms are the algorithms Hydra can generate keys for, as documented for the alg of CreateJsonWebKeySet.
+var jwkGeneratorAlgorithms = []string{"RS256", "ES256", "ES512", "HS256", "HS512"}
+
+func validateJWKAlgorithm() schema.SchemaValidateFunc {
+	algorithms := make([]string, 0, len(jwkAlgorithms))
+	for alg := range jwkAlgorithms {
+		algorithms = append(algorithms, alg)
+	}
+	sort.Strings(algorithms)
+	return validation.StringInSlice(algorithms, false)
+}
+
+func validateJWKKeyType() schema.SchemaValidateFunc {
+	return validation.StringInSlice([]string{"RSA", "EC", "OKP", "oct"}, false)
+}
+
+func validateJWKCurve() schema.SchemaValidateFunc {
+	return validation.StringInSlice(append(slices.Clone(ecCurves), okpCurves...), false)
+}
+
+// validateJWK checks that the algorithm, key type, curve and use of jwk are consistent,
+// and that jwk has exactly the members its key type requires.
+func validateJWK(jwk *hydra.JsonWebKey) error {
+	members := map[string]string{
+		"crv": jwk.GetCrv(),
+		"d":   jwk.GetD(),
+		"dp":  jwk.GetDp(),
+		"dq":  jwk.GetDq(),
+		"e":   jwk.GetE(),
+		"k":   jwk.GetK(),
+		"n":   jwk.GetN(),
+		"p":   jwk.GetP(),
+		"q":   jwk.GetQ(),
+		"qi":  jwk.GetQi(),
+		"x":   jwk.GetX(),
+		"y":   jwk.GetY(),
+	}
+
+	kty, ok := jwkMembers[jwk.Kty]
+	if !ok {
+		return fmt.Errorf("unsupported kty %q", jwk.Kty)
+	}
+	for _, member := range kty.required {
+		if members[member] == "" {
+			return fmt.Errorf("kty %q requires %q", jwk.Kty, member)
+		}
+	}
+	for member, value := range members {
+		if value != "" && !slices.Contains(kty.required, member) && !slices.Contains(kty.optional, member) {
+			return fmt.Errorf("%q isn't a member of kty %q", member, jwk.Kty)
+		}
+	}
+
+	if alg, ok := jwkAlgorithms[jwk.Alg]; ok {
+		if !slices.Contains(alg.kty, jwk.Kty) {
+			return fmt.Errorf("alg %q requires kty %q, got %q", jwk.Alg, alg.kty, jwk.Kty)
+		}
+		if jwk.Use != alg.use {
+			return fmt.Errorf("alg %q requires use %q, got %q", jwk.Alg, alg.use, jwk.Use)
+		}
+		if len(alg.curves) > 0 && !slices.Contains(alg.curves, jwk.GetCrv()) {
+			return fmt.Errorf("alg %q requires crv %q, got %q", jwk.Alg, alg.curves, jwk.GetCrv())
+		}
+	}
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.GetN())
+		if err != nil {
+			return fmt.Errorf("%q must be base64url encoded: %w", "n", err)
+		}
+		if size := new(big.Int).SetBytes(n).BitLen(); size < minRSAKeySize {
+			return fmt.Errorf("RSA keys must be at least %d bits, got %d", minRSAKeySize, size)
+		}
+
+		// RFC 7518 section 6.3.2: the other private members are only allowed together, and only with d.
+		crt := 0
+		for _, member := range []string{"p", "q", "dp", "dq", "qi"} {
+			if members[member] != "" {
+				crt++
+			}
+		}
+		if crt > 0 && (crt < 5 || members["d"] == "") {
+			return fmt.Errorf(`RSA private keys require either only "d" or all of "d", "p", "q", "dp", "dq" and "qi"`)
+		}
+	case "EC":
+		if !slices.Contains(ecCurves, jwk.GetCrv()) {
+			return fmt.Errorf("kty %q requires crv %q, got %q", jwk.Kty, ecCurves, jwk.GetCrv())
+		}
+	case "OKP":
+		if !slices.Contains(okpCurves, jwk.GetCrv()) {
+			return fmt.Errorf("kty %q requires crv %q, got %q", jwk.Kty, okpCurves, jwk.GetCrv())
+		}
+	}
+
+	return nil
+}
+
+// validateJWKGenerator checks that Hydra can generate a key for the use of a generator.
+// Generated keys are signing keys, except that RSA keys may also be generated for encryption.
+func validateJWKGenerator(generator map[string]interface{}) error {
+	alg, use := generator["alg"].(string), generator["use"].(string)
+	algorithm, ok := jwkAlgorithms[alg]
+	if !ok || use == algorithm.use || (use == "enc" && slices.Contains(algorithm.kty, "RSA")) {
+		return nil
+	}
+	return fmt.Errorf("alg %q requires use %q, got %q", alg, algorithm.use, use)
+}
+
+// validatePublicJWK checks that jwk is a public key, since the keys of clients are registered with and shown by Hydra.
+func validatePublicJWK(jwk *hydra.JsonWebKey) error {
+	if jwk.Kty == "oct" {
+		return fmt.Errorf("kty %q is a symmetric key, only public keys can be registered", jwk.Kty)
+	}
+	for _, member := range []struct {
+		name  string
+		value *string
+	}{{"d", jwk.D}, {"p", jwk.P}, {"q", jwk.Q}, {"dp", jwk.Dp}, {"dq", jwk.Dq}, {"qi", jwk.Qi}, {"k", jwk.K}} {
+		if member.value != nil && *member.value != "" {
+			return fmt.Errorf("%q is a private member, only public keys can be registered", member.name)
+		}
+	}
+	return nil
+}
+
+// customizeDiffJWKs validates the keys configured in the list attribute key with validateJWK and any further validators.
+// Keys with values which aren't known yet are skipped.
+// Only the configuration is validated, since keys read from Hydra were accepted already.
+func customizeDiffJWKs(key string, validators ...func(*hydra.JsonWebKey) error) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+		rawConfig := diff.GetRawConfig()
+		if rawConfig.IsNull() || !rawConfig.IsKnown() {
+			return nil
+		}
+
+		keys := rawConfig.GetAttr(key)
+		if keys.IsNull() || !keys.IsKnown() {
+			return nil
+		}
+
+		for i, v := range keys.AsValueSlice() {
+			data, ok := jwkDataFromConfig(v)
+			if !ok {
+				continue
+			}
+
+			jwk, err := dataToJWK(data)
+			if err != nil {
+				return fmt.Errorf("%s.%d: %w", key, i, err)
+			}
+			for _, validate := range append([]func(*hydra.JsonWebKey) error{validateJWK}, validators...) {
+				if err := validate(jwk); err != nil {
+					return fmt.Errorf("%s.%d: %w", key, i, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// customizeDiffJWK validates a key configured at the top level of a resource.
+func customizeDiffJWK(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	rawConfig := diff.GetRawConfig()
+	if rawConfig.IsNull() {
+		return nil
+	}
+
+	data, ok := jwkDataFromConfig(rawConfig)
+	if !ok {
+		return nil
+	}
+	jwk, err := dataToJWK(data)
+	if err != nil {
+		return err
+	}
+	return validateJWK(jwk)
+}
+
+// jwkDataFromConfig converts the configuration of a key into the form dataToJWK expects.
+// ok is false if any of its values isn't known yet.
+func jwkDataFromConfig(config cty.Value) (map[string]interface{}, bool) {
+	if config.IsNull() || !config.IsWhollyKnown() {
+		return nil, false
+	}
+
+	data := make(map[string]interface{})
+	for name, attrType := range config.Type().AttributeTypes() {
+		value := config.GetAttr(name)
+		switch {
+		case attrType == cty.String:
+			data[name] = ""
+			if !value.IsNull() {
+				data[name] = value.AsString()
+			}
+		case attrType.IsListType() && attrType.ElementType() == cty.String:
+			var items []interface{}
+			if !value.IsNull() {
+				for _, item := range value.AsValueSlice() {
+					items = append(items, item.AsString())
+				}
+			}
+			data[name] = items
+		}
+	}
+	return data, true
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc7638N is the 2048 bit modulus of the example key of RFC 7638, section 3.1.
+const rfc7638N = "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+
+func TestValidateJWK(t *testing.T) {
+	rsaKey := func(alg, use string) *hydra.JsonWebKey {
+		return &hydra.JsonWebKey{Alg: alg, Use: use, Kty: "RSA", N: ptr(rfc7638N), E: ptr("AQAB")}
+	}
+
+	for _, tc := range []struct {
+		name string
+		jwk  *hydra.JsonWebKey
+		err  string
+	}{
+		{name: "RSA signing key", jwk: rsaKey("RS256", "sig")},
+		{name: "RSA encryption key", jwk: rsaKey("RSA-OAEP-256", "enc")},
+		{name: "EC key", jwk: &hydra.JsonWebKey{Alg: "ES384", Use: "sig", Kty: "EC", Crv: ptr("P-384"), X: ptr("x"), Y: ptr("y")}},
+		{name: "ECDH-ES with X25519", jwk: &hydra.JsonWebKey{Alg: "ECDH-ES", Use: "enc", Kty: "OKP", Crv: ptr("X25519"), X: ptr("x")}},
+		{name: "symmetric key", jwk: &hydra.JsonWebKey{Alg: "HS256", Use: "sig", Kty: "oct", K: ptr("k")}},
+		{name: "use doesn't match alg", jwk: rsaKey("RS256", "enc"), err: `alg "RS256" requires use "sig", got "enc"`},
+		{name: "kty doesn't match alg", jwk: rsaKey("ES256", "sig"), err: `alg "ES256" requires kty ["EC"], got "RSA"`},
+		{name: "crv doesn't match alg", jwk: &hydra.JsonWebKey{Alg: "ES256", Use: "sig", Kty: "EC", Crv: ptr("P-521"), X: ptr("x"), Y: ptr("y")}, err: `alg "ES256" requires crv ["P-256"], got "P-521"`},
+		{name: "EdDSA with X25519", jwk: &hydra.JsonWebKey{Alg: "EdDSA", Use: "sig", Kty: "OKP", Crv: ptr("X25519"), X: ptr("x")}, err: `alg "EdDSA" requires crv ["Ed25519" "Ed448"], got "X25519"`},
+		{name: "EC curve for OKP", jwk: &hydra.JsonWebKey{Alg: "ECDH-ES", Use: "enc", Kty: "OKP", Crv: ptr("P-256"), X: ptr("x")}, err: `kty "OKP" requires crv`},
+		{name: "missing member", jwk: &hydra.JsonWebKey{Alg: "ES256", Use: "sig", Kty: "EC", Crv: ptr("P-256"), X: ptr("x")}, err: `kty "EC" requires "y"`},
+		{name: "foreign member", jwk: &hydra.JsonWebKey{Alg: "HS256", Use: "sig", Kty: "oct", K: ptr("k"), N: ptr("n")}, err: `"n" isn't a member of kty "oct"`},
+		{name: "unknown kty", jwk: &hydra.JsonWebKey{Alg: "RS256", Use: "sig", Kty: "rsa"}, err: `unsupported kty "rsa"`},
+		{name: "small RSA key", jwk: &hydra.JsonWebKey{Alg: "RS256", Use: "sig", Kty: "RSA", N: ptr(rfc7638N[:171]), E: ptr("AQAB")}, err: "RSA keys must be at least 2048 bits, got 1024"},
+		{name: "incomplete RSA private key", jwk: func() *hydra.JsonWebKey {
+			jwk := rsaKey("RS256", "sig")
+			jwk.D = ptr("d")
+			jwk.P = ptr("p")
+			return jwk
+		}(), err: `RSA private keys require either only "d" or all of`},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			err := validateJWK(tc.jwk)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestValidatePublicJWK(t *testing.T) {
+	require.NoError(t, validatePublicJWK(&hydra.JsonWebKey{Alg: "RS256", Use: "sig", Kty: "RSA", N: ptr(rfc7638N), E: ptr("AQAB")}))
+	require.NoError(t, validatePublicJWK(&hydra.JsonWebKey{Alg: "ES256", Use: "sig", Kty: "EC", Crv: ptr("P-256"), X: ptr("x"), Y: ptr("y")}))
+
+	require.ErrorContains(t, validatePublicJWK(&hydra.JsonWebKey{Alg: "HS256", Use: "sig", Kty: "oct", K: ptr("k")}), `kty "oct" is a symmetric key`)
+	require.ErrorContains(t, validatePublicJWK(&hydra.JsonWebKey{Alg: "ES256", Use: "sig", Kty: "EC", Crv: ptr("P-256"), X: ptr("x"), Y: ptr("y"), D: ptr("d")}), `"d" is a private member`)
+	require.ErrorContains(t, validatePublicJWK(&hydra.JsonWebKey{Alg: "RS256", Use: "sig", Kty: "RSA", N: ptr(rfc7638N), E: ptr("AQAB"), Qi: ptr("qi")}), `"qi" is a private member`)
+}
+
+func TestCustomizeDiffJWKs(t *testing.T) {
+	r := resourceJWKS()
+
+	diff := func(key map[string]cty.Value) error {
+		config, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("keys"),
+			"key":  cty.ListVal([]cty.Value{cty.ObjectVal(key)}),
+		}))
+		require.NoError(t, err)
+
+		// Like the plugin server, pass the configuration along with the prior state.
+		state := &terraform.InstanceState{RawConfig: config}
+		_, err = r.Diff(context.Background(), state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), nil)
+		return err
+	}
+
+	key := map[string]cty.Value{
+		"alg": cty.StringVal("RS256"),
+		"kid": cty.StringVal("key"),
+		"use": cty.StringVal("sig"),
+		"kty": cty.StringVal("RSA"),
+		"n":   cty.StringVal(rfc7638N),
+		"e":   cty.StringVal("AQAB"),
+	}
+	require.NoError(t, diff(key))
+
+	key["use"] = cty.StringVal("enc")
+	require.ErrorContains(t, diff(key), `key.0: alg "RS256" requires use "sig", got "enc"`)
+
+	key["n"] = cty.UnknownVal(cty.String)
+	require.NoError(t, diff(key), "keys with unknown values are validated once they are known")
+}
+
+func TestValidateJWKGenerator(t *testing.T) {
+	for _, tc := range []struct {
+		alg, use string
+		err      string
+	}{
+		{alg: "RS256", use: "sig"},
+		{alg: "RS256", use: "enc"},
+		{alg: "ES256", use: "sig"},
+		{alg: "ES512", use: "sig"},
+		{alg: "HS256", use: "sig"},
+		{alg: "HS512", use: "sig"},
+		{alg: "ES256", use: "enc", err: `alg "ES256" requires use "sig", got "enc"`},
+		{alg: "HS256", use: "enc", err: `alg "HS256" requires use "sig", got "enc"`},
+	} {
+		t.Run("case="+tc.alg+"/"+tc.use, func(t *testing.T) {
+			err := validateJWKGenerator(map[string]interface{}{"alg": tc.alg, "use": tc.use})
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/santhosh-tekuri/jsonschema/v5"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
@@ -23,8 +24,10 @@ func init() {
 }
 
 type ClientConfig struct {
-	hydraClient *hydra.APIClient
-	backOff     *backoff.ExponentialBackOff
+	hydraClient     *hydra.APIClient
+	backOff         *backoff.ExponentialBackOff
+	allowedURIHosts []string
+	metadataSchema  *jsonschema.Schema
 }
 
 func New() *schema.Provider {
@@ -35,6 +38,19 @@ func New() *schema.Provider {
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HYDRA_ADMIN_URL", nil),
 			},
+			"allowed_uri_hosts": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "If set, redirect, logout and other URIs of OAuth2 clients must point to one of these hosts. Wildcard patterns like `*.example.com` match any subdomain.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"metadata_schema_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a JSON Schema document which the metadata of OAuth2 clients is validated against at plan time.",
+			},
 			"retry_policy": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -200,11 +216,18 @@ func New() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"hydra_oauth2_client": resourceOAuth2Client(),
-			"hydra_jwks":          resourceJWKS(),
+			"hydra_oauth2_client":                   resourceOAuth2Client(),
+			"hydra_oauth2_client_set":               resourceOAuth2ClientSet(),
+			"hydra_oidc_dynamic_client":             resourceOIDCDynamicClient(),
+			"hydra_trusted_oauth2_jwt_grant_issuer": resourceTrustedOAuth2JwtGrantIssuer(),
+			"hydra_jwks":                            resourceJWKS(),
+			"hydra_jwk":                             resourceSingleJWK(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"hydra_jwks": dataSourceJWKS(),
+			"hydra_jwks":                             dataSourceJWKS(),
+			"hydra_oauth2_client":                    dataSourceOAuth2Client(),
+			"hydra_oauth2_clients":                   dataSourceOAuth2Clients(),
+			"hydra_trusted_oauth2_jwt_grant_issuers": dataSourceTrustedOAuth2JwtGrantIssuers(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -246,9 +269,19 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 		backOff.RandomizationFactor = randomizationFactor
 	}
 
+	var metadataSchema *jsonschema.Schema
+	if metadataSchemaFile, ok := data.GetOk("metadata_schema_file"); ok {
+		metadataSchema, err = jsonschema.Compile(metadataSchemaFile.(string))
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
 	return &ClientConfig{
-		hydraClient: hydra.NewAPIClient(cfg),
-		backOff:     backOff,
+		hydraClient:     hydra.NewAPIClient(cfg),
+		backOff:         backOff,
+		allowedURIHosts: strSlice(data.Get("allowed_uri_hosts").([]interface{})),
+		metadataSchema:  metadataSchema,
 	}, nil
 }
 
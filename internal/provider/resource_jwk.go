@@ -1,6 +1,15 @@
 package provider
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	hydra "github.com/ory/hydra-client-go/v2"
@@ -10,8 +19,9 @@ func resourceJWK() *schema.Resource {
 	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
 			"alg": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateJWKAlgorithm(),
 			},
 			"kid": {
 				Type:     schema.TypeString,
@@ -25,13 +35,15 @@ func resourceJWK() *schema.Resource {
 				}, false),
 			},
 			"kty": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateJWKKeyType(),
 			},
 			"crv": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateJWKCurve(),
 			},
 			"d": {
 				Type:      schema.TypeString,
@@ -110,7 +122,81 @@ func resourceJWK() *schema.Resource {
 	}
 }
 
-func dataToJWK(data map[string]interface{}) *hydra.JsonWebKey {
+// resourceJWKFromPEM relaxes kid and kty of resourceJWK, which are computed for keys set from PEM inputs.
+func resourceJWKFromPEM() *schema.Resource {
+	r := resourceJWK()
+
+	for _, key := range []string{"kid", "kty"} {
+		r.Schema[key].Required = false
+		r.Schema[key].Optional = true
+		r.Schema[key].Computed = true
+	}
+	r.Schema["kid"].Description = "Key ID. Defaults to the RFC 7638 thumbprint of the key if it is set from a PEM input."
+	r.Schema["kty"].Description = "Key type. Computed if the key is set from a PEM input."
+
+	return r
+}
+
+// resourceClientJWK extends resourceJWK with PEM inputs for the public keys of OAuth2 clients.
+func resourceClientJWK() *schema.Resource {
+	r := resourceJWKFromPEM()
+
+	r.Schema["public_key_pem"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateFunc:     validatePublicKeyPEM,
+		DiffSuppressFunc: diffSuppressImportedJWKPEM,
+		Description:      "PEM encoded public key (`PUBLIC KEY` or `RSA PUBLIC KEY`), which is converted to the JWK members. Private keys are rejected.",
+	}
+	r.Schema["certificate_pem"] = &schema.Schema{
+		Type:             schema.TypeString,
+		Optional:         true,
+		ValidateFunc:     validateCertificatePEM,
+		DiffSuppressFunc: diffSuppressImportedJWKPEM,
+		Description:      "PEM encoded X.509 certificate, optionally followed by its chain, which is converted to the JWK members including `x5c`.",
+	}
+
+	return r
+}
+
+// resourceJWKSKey extends resourceJWK with PEM inputs for the keys of hydra_jwks, which may be private keys.
+func resourceJWKSKey() *schema.Resource {
+	r := resourceJWKFromPEM()
+
+	r.Schema["private_key_pem"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Sensitive:    true,
+		ValidateFunc: validatePrivateKeyPEM,
+		Description:  "PEM encoded private key (`PRIVATE KEY`, `RSA PRIVATE KEY` or `EC PRIVATE KEY`), which is converted to the JWK members including the private ones.",
+	}
+	r.Schema["certificate_chain_pem"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateCertificatePEM,
+		Description:  "PEM encoded X.509 certificate of `private_key_pem`, optionally followed by its chain, which is converted to `x5c`.",
+	}
+
+	return r
+}
+
+// addJWKOutputs adds the attributes dataFromPublicJWKS computes for each key.
+func addJWKOutputs(r *schema.Resource) *schema.Resource {
+	r.Schema["thumbprint_sha256"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "RFC 7638 thumbprint of the key, using SHA-256.",
+	}
+	r.Schema["public_key_pem"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "PEM encoded public key (`PUBLIC KEY`). Empty for symmetric keys.",
+	}
+	return r
+}
+
+// dataToJWK converts a key of the schema into a JWK, with the members of its PEM inputs if any are set.
+func dataToJWK(data map[string]interface{}) (*hydra.JsonWebKey, error) {
 	jwk := &hydra.JsonWebKey{
 		Alg: data["alg"].(string),
 		Kid: data["kid"].(string),
@@ -156,7 +242,152 @@ func dataToJWK(data map[string]interface{}) *hydra.JsonWebKey {
 	if y := data["y"].(string); y != "" {
 		jwk.Y = &y
 	}
-	return jwk
+	if err := setJWKFromPEMInputs(jwk, data); err != nil {
+		return nil, err
+	}
+	return jwk, nil
+}
+
+// setJWKFromPEMInputs fills the members of jwk from the PEM inputs of resourceClientJWK or resourceJWKSKey, if any are set.
+func setJWKFromPEMInputs(jwk *hydra.JsonWebKey, data map[string]interface{}) error {
+	privateKeyPEM, _ := data["private_key_pem"].(string)
+	certificateChainPEM, _ := data["certificate_chain_pem"].(string)
+	if privateKeyPEM != "" {
+		return setJWKFromPrivateKeyPEM(jwk, privateKeyPEM, certificateChainPEM)
+	}
+	if certificateChainPEM != "" {
+		return errors.New("certificate_chain_pem requires private_key_pem")
+	}
+
+	publicKeyPEM, _ := data["public_key_pem"].(string)
+	certificatePEM, _ := data["certificate_pem"].(string)
+	return setJWKFromPEM(jwk, publicKeyPEM, certificatePEM)
+}
+
+// diffSuppressImportedJWKPEM suppresses setting a PEM input of resourceClientJWK on a key without one in state,
+// as after an import, if the PEM converts to the members of the key in state. Hydra doesn't return the PEM inputs,
+// so imported keys would otherwise be replaced by the same key.
+func diffSuppressImportedJWKPEM(k, old, new string, d *schema.ResourceData) bool {
+	if old != "" || new == "" {
+		return false
+	}
+
+	prefix := k[:strings.LastIndex(k, ".")]
+	prior, _ := d.GetChange(prefix)
+	priorKey, ok := prior.(map[string]interface{})
+	if !ok || len(priorKey) == 0 {
+		return false
+	}
+	priorJWK, err := dataToJWK(priorKey)
+	if err != nil {
+		return false
+	}
+
+	jwk := &hydra.JsonWebKey{Alg: priorJWK.Alg, Kid: priorJWK.Kid, Use: priorJWK.Use}
+	switch strings.TrimPrefix(k, prefix+".") {
+	case "public_key_pem":
+		err = setJWKFromPEM(jwk, new, "")
+	case "certificate_pem":
+		err = setJWKFromPEM(jwk, "", new)
+	default:
+		return false
+	}
+	return err == nil && reflect.DeepEqual(jwk, priorJWK)
+}
+
+// customizeDiffJWKsFromPEM plans the members of the keys in the list attribute key which are set from any of the PEM inputs.
+// Otherwise the members Hydra returned for the previous key would be kept in the plan, including its thumbprint as kid.
+func customizeDiffJWKsFromPEM(key string, inputs ...string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		rawConfig := d.GetRawConfig()
+		if rawConfig.IsNull() || !rawConfig.IsKnown() {
+			return nil
+		}
+		configuredKeys := rawConfig.GetAttr(key)
+		if configuredKeys.IsNull() || !configuredKeys.IsKnown() {
+			return nil
+		}
+
+		keys := d.Get(key).([]interface{})
+		changed := false
+		for i, v := range configuredKeys.AsValueSlice() {
+			config, ok := jwkDataFromConfig(v)
+			if !ok || i >= len(keys) || !slices.ContainsFunc(inputs, func(input string) bool { return config[input] != "" }) {
+				continue
+			}
+
+			jwk, err := dataToJWK(config)
+			if err != nil {
+				return fmt.Errorf("%s.%d: %w", key, i, err)
+			}
+			planned := keys[i].(map[string]interface{})
+			for member, value := range dataFromJWK(jwk) {
+				planned[member] = value
+			}
+			changed = true
+		}
+
+		if !changed {
+			return nil
+		}
+		return d.SetNew(key, keys)
+	}
+}
+
+// orderJWKs orders keys like the prior keys by key id, so that Hydra returning the keys of a set in a different order
+// doesn't show up as a change of every key. Other keys take the places of prior keys which are gone or have no key id yet,
+// in the order Hydra returned them, and the remaining ones are appended.
+func orderJWKs(prior []interface{}, keys []hydra.JsonWebKey) []hydra.JsonWebKey {
+	positions := make(map[string]int, len(prior))
+	for i, priorKey := range prior {
+		if priorKey == nil {
+			continue
+		}
+		if kid, _ := priorKey.(map[string]interface{})["kid"].(string); kid != "" {
+			if _, ok := positions[kid]; !ok {
+				positions[kid] = i
+			}
+		}
+	}
+
+	slots := make([]*hydra.JsonWebKey, len(prior))
+	var others []hydra.JsonWebKey
+	for i := range keys {
+		if position, ok := positions[keys[i].Kid]; ok && slots[position] == nil {
+			slots[position] = &keys[i]
+			continue
+		}
+		others = append(others, keys[i])
+	}
+
+	ordered := make([]hydra.JsonWebKey, 0, len(keys))
+	for _, slot := range slots {
+		if slot == nil && len(others) > 0 {
+			slot, others = &others[0], others[1:]
+		}
+		if slot != nil {
+			ordered = append(ordered, *slot)
+		}
+	}
+	return append(ordered, others...)
+}
+
+// mergeJWKInputs copies inputs which Hydra doesn't return from the prior keys into keys.
+// Keys are matched by position, as long as the prior key has the same or no key id yet.
+func mergeJWKInputs(prior []interface{}, keys []map[string]interface{}, inputs ...string) {
+	for i, key := range keys {
+		if i >= len(prior) || prior[i] == nil {
+			continue
+		}
+
+		priorKey := prior[i].(map[string]interface{})
+		if priorKid, _ := priorKey["kid"].(string); priorKid != "" && priorKid != key["kid"] {
+			continue
+		}
+		for _, input := range inputs {
+			key[input] = priorKey[input]
+		}
+	}
 }
 
 func dataFromJWK(jwk *hydra.JsonWebKey) map[string]interface{} {
@@ -180,3 +411,158 @@ func dataFromJWK(jwk *hydra.JsonWebKey) map[string]interface{} {
 		"y":   jwk.Y,
 	}
 }
+
+// resourceSingleJWK manages a single key of a JSON Web Key Set, so that keys of the same set can be managed independently.
+func resourceSingleJWK() *schema.Resource {
+	s := resourceJWK().Schema
+	s["set"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "Name of the JSON Web Key Set the key belongs to.",
+	}
+	s["kid"].ForceNew = true
+	s["kid"].Description = "Key ID, which identifies the key within its set."
+
+	return &schema.Resource{
+		Description: `A single JSON Web Key (JWK) within a JSON Web Key Set, identified by its set and key id.
+Unlike hydra_jwks, which replaces the whole set, only this key is created, updated and deleted, so other keys of the set can be managed elsewhere.`,
+		Importer: &schema.ResourceImporter{
+			StateContext: importSingleJWKResource,
+		},
+		Schema:        s,
+		CustomizeDiff: customizeDiffJWK,
+		CreateContext: createSingleJWKResource,
+		ReadContext:   readSingleJWKResource,
+		UpdateContext: updateSingleJWKResource,
+		DeleteContext: deleteSingleJWKResource,
+	}
+}
+
+func singleJWKID(set, kid string) string {
+	return set + "/" + kid
+}
+
+// importSingleJWKResource accepts IDs of the form <set>/<kid>. Set names may contain slashes, key IDs may not.
+func importSingleJWKResource(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	i := strings.LastIndex(data.Id(), "/")
+	if i <= 0 || i == len(data.Id())-1 {
+		return nil, fmt.Errorf("unexpected ID %q, expected <set>/<kid>", data.Id())
+	}
+
+	data.Set("set", data.Id()[:i])
+	data.Set("kid", data.Id()[i+1:])
+
+	return []*schema.ResourceData{data}, nil
+}
+
+func createSingleJWKResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	set := data.Get("set").(string)
+	kid := data.Get("kid").(string)
+
+	// Setting a key replaces an existing key with the same ID, which would take over a key managed elsewhere.
+	var resp *http.Response
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		_, resp, err = hydraClient.JwkApi.GetJsonWebKey(ctx, set, kid).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err == nil {
+		return diag.Errorf("key %q already exists in JSON Web Key Set %q, import it to manage it with Terraform", kid, set)
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(singleJWKID(set, kid))
+
+	return updateSingleJWKResource(ctx, data, meta)
+}
+
+func readSingleJWKResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	set := data.Get("set").(string)
+	kid := data.Get("kid").(string)
+
+	var jsonWebKeySet *hydra.JsonWebKeySet
+	var resp *http.Response
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		jsonWebKeySet, resp, err = hydraClient.JwkApi.GetJsonWebKey(ctx, set, kid).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			data.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	for _, jwk := range jsonWebKeySet.Keys {
+		if jwk.Kid == kid {
+			for key, value := range dataFromJWK(&jwk) {
+				data.Set(key, value)
+			}
+			return nil
+		}
+	}
+
+	data.SetId("")
+	return nil
+}
+
+func updateSingleJWKResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	set := data.Get("set").(string)
+	kid := data.Get("kid").(string)
+
+	jwk, err := dataToJWK(map[string]interface{}{
+		"alg": data.Get("alg"),
+		"kid": kid,
+		"use": data.Get("use"),
+		"kty": data.Get("kty"),
+		"crv": data.Get("crv"),
+		"d":   data.Get("d"),
+		"dp":  data.Get("dp"),
+		"dq":  data.Get("dq"),
+		"e":   data.Get("e"),
+		"k":   data.Get("k"),
+		"n":   data.Get("n"),
+		"p":   data.Get("p"),
+		"q":   data.Get("q"),
+		"qi":  data.Get("qi"),
+		"x":   data.Get("x"),
+		"x5c": data.Get("x5c"),
+		"y":   data.Get("y"),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		_, resp, err := hydraClient.JwkApi.SetJsonWebKey(ctx, set, kid).JsonWebKey(*jwk).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return readSingleJWKResource(ctx, data, meta)
+}
+
+func deleteSingleJWKResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		return hydraClient.JwkApi.DeleteJsonWebKey(ctx, data.Get("set").(string), data.Get("kid").(string)).Execute()
+	}, meta.(*ClientConfig).backOff)
+
+	return diag.FromErr(err)
+}
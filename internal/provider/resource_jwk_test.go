@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// jwkStub keeps keys in memory by set and key ID, like the key endpoints of the Admin API.
+type jwkStub struct {
+	sync.Mutex
+	keys map[string]map[string]interface{}
+}
+
+func (s *jwkStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	id := strings.TrimPrefix(req.URL.Path, "/admin/keys/")
+	key, exists := s.keys[id]
+	switch {
+	case req.Method == http.MethodPut:
+		key = map[string]interface{}{}
+		_ = json.NewDecoder(req.Body).Decode(&key)
+		s.keys[id] = key
+		_ = json.NewEncoder(w).Encode(key)
+	case !exists:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error": "Unable to locate the resource", "status_code": 404}`))
+	case req.Method == http.MethodDelete:
+		delete(s.keys, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{key}})
+	}
+}
+
+func TestResourceSingleJWK(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwkStub{keys: map[string]map[string]interface{}{
+		"shared/other": {"alg": "RS256", "kid": "other", "kty": "RSA", "use": "sig", "e": "AQAB", "n": "b3RoZXI"},
+	}}
+	meta := newHydraStub(t, stub)
+
+	r := resourceSingleJWK()
+
+	config := map[string]interface{}{
+		"set": "shared",
+		"kid": "mine",
+		"alg": "RS256",
+		"kty": "RSA",
+		"use": "sig",
+		"e":   "AQAB",
+		"n":   "bWluZQ",
+	}
+
+	apply := func(t *testing.T, state *terraform.InstanceState, config map[string]interface{}) (*terraform.InstanceState, diag.Diagnostics) {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+		if diff == nil {
+			return state, nil
+		}
+
+		return r.Apply(ctx, state, diff, meta)
+	}
+
+	var state *terraform.InstanceState
+
+	t.Run("case=creates the key without touching other keys of the set", func(t *testing.T) {
+		var diags diag.Diagnostics
+		state, diags = apply(t, state, config)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		require.Equal(t, "shared/mine", state.ID)
+		require.Equal(t, "bWluZQ", stub.keys["shared/mine"]["n"])
+		require.Contains(t, stub.keys, "shared/other")
+	})
+
+	t.Run("case=updates the key in place", func(t *testing.T) {
+		config["alg"] = "RS512"
+		var diags diag.Diagnostics
+		state, diags = apply(t, state, config)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		require.Equal(t, "shared/mine", state.ID)
+		require.Equal(t, "RS512", stub.keys["shared/mine"]["alg"])
+	})
+
+	t.Run("case=refuses to take over an existing key", func(t *testing.T) {
+		other := map[string]interface{}{}
+		for k, v := range config {
+			other[k] = v
+		}
+		other["kid"] = "other"
+
+		_, diags := apply(t, nil, other)
+		require.True(t, diags.HasError())
+		require.Contains(t, diags[0].Summary, `key "other" already exists in JSON Web Key Set "shared"`)
+		require.Equal(t, "b3RoZXI", stub.keys["shared/other"]["n"])
+	})
+
+	t.Run("case=removes the key deleted outside of Terraform from state", func(t *testing.T) {
+		key := stub.keys["shared/mine"]
+		delete(stub.keys, "shared/mine")
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Nil(t, refreshed)
+
+		stub.keys["shared/mine"] = key
+	})
+
+	t.Run("case=deletes only the key", func(t *testing.T) {
+		state.Attributes["id"] = state.ID
+		_, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotContains(t, stub.keys, "shared/mine")
+		require.Contains(t, stub.keys, "shared/other")
+	})
+}
+
+func TestImportSingleJWKResource(t *testing.T) {
+	for _, tc := range []struct {
+		id, set, kid, err string
+	}{
+		{id: "hydra.openid.id-token/public:abc", set: "hydra.openid.id-token", kid: "public:abc"},
+		{id: "https://idp.example.com/abc", set: "https://idp.example.com", kid: "abc"},
+		{id: "abc", err: `unexpected ID "abc"`},
+		{id: "set/", err: `unexpected ID "set/"`},
+	} {
+		t.Run("case="+tc.id, func(t *testing.T) {
+			data := resourceSingleJWK().Data(&terraform.InstanceState{ID: tc.id})
+
+			_, err := importSingleJWKResource(context.Background(), data, nil)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.set, data.Get("set"))
+			require.Equal(t, tc.kid, data.Get("kid"))
+		})
+	}
+}
+
+func TestOrderJWKs(t *testing.T) {
+	prior := func(kids ...string) []interface{} {
+		keys := make([]interface{}, len(kids))
+		for i, kid := range kids {
+			keys[i] = map[string]interface{}{"kid": kid}
+		}
+		return keys
+	}
+
+	for _, tc := range []struct {
+		name     string
+		prior    []interface{}
+		kids     []string
+		expected []string
+	}{
+		{name: "no prior keys", kids: []string{"b", "a"}, expected: []string{"b", "a"}},
+		{name: "reordered", prior: prior("a", "b", "c"), kids: []string{"c", "a", "b"}, expected: []string{"a", "b", "c"}},
+		{name: "added", prior: prior("a", "b"), kids: []string{"c", "b", "a"}, expected: []string{"a", "b", "c"}},
+		{name: "removed", prior: prior("a", "b", "c"), kids: []string{"c", "a"}, expected: []string{"a", "c"}},
+		{name: "replaced", prior: prior("a", "b", "c"), kids: []string{"c", "x", "a"}, expected: []string{"a", "x", "c"}},
+		{name: "prior key without kid", prior: prior("a", "", "c"), kids: []string{"c", "thumbprint", "a"}, expected: []string{"a", "thumbprint", "c"}},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			keys := make([]hydra.JsonWebKey, len(tc.kids))
+			for i, kid := range tc.kids {
+				keys[i] = hydra.JsonWebKey{Kid: kid}
+			}
+
+			var kids []string
+			for _, key := range orderJWKs(tc.prior, keys) {
+				kids = append(kids, key.Kid)
+			}
+			require.Equal(t, tc.expected, kids)
+		})
+	}
+}
@@ -2,9 +2,18 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	hydra "github.com/ory/hydra-client-go/v2"
@@ -24,21 +33,40 @@ A JSON Web Key is identified by its set and key id. ORY Hydra uses this function
 				Required: true,
 			},
 			"key": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
-				Elem:     resourceJWK(),
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				Elem:          addJWKOutputs(resourceJWKSKey()),
+				ConflictsWith: []string{"generator"},
+				Description:   "Keys of the set. Keys read from Hydra are matched to the known ones by kid, regardless of the order Hydra returns them in, and reordering the key blocks doesn't update the set.",
+			},
+			"jwks_json": {
+				Type:                  schema.TypeString,
+				Optional:              true,
+				Sensitive:             true,
+				ValidateFunc:          validateJWKSJSON,
+				StateFunc:             normalizeJWKSJSON,
+				DiffSuppressFunc:      diffSuppressEquivalentJWKSJSON,
+				DiffSuppressOnRefresh: true,
+				ConflictsWith:         []string{"key", "generator"},
+				Description:           "JSON Web Key Set document with the keys of the set, e.g. read with `file`. Keys are compared by kid, regardless of their order and formatting.",
+			},
+			"public_jwks_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON Web Key Set of the public keys in the set, without private members and symmetric keys, e.g. to be published to relying parties.",
 			},
 			"generator": {
-				Type:     schema.TypeList,
-				Optional: true,
-				ForceNew: true,
-				MaxItems: 1,
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Generates keys in the set. Generators are identified by their kid, each one can be regenerated without touching the keys of the others. A kid may not have the form `<kid of another generator>-<digits>` of rotated keys. Removing a generator deletes its keys. Conflicts with `key`.",
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"alg": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(jwkGeneratorAlgorithms, false),
+							Description:  "Algorithm of the generated key, one of `RS256`, `ES256`, `ES512`, `HS256` and `HS512`, which Hydra can generate keys for.",
 						},
 						"kid": {
 							Type:     schema.TypeString,
@@ -50,17 +78,38 @@ A JSON Web Key is identified by its set and key id. ORY Hydra uses this function
 							ValidateFunc: validation.StringInSlice([]string{
 								"sig", "enc",
 							}, false),
+							Description: "Use of the generated key, `sig` or `enc`. Only `RS256` keys can be generated for encryption.",
 						},
 						"keepers": {
 							Type:        schema.TypeMap,
 							Required:    true,
-							ForceNew:    true,
-							Description: "Arbitrary map of values that, when changed, will trigger recreation of resource.",
+							Description: "Arbitrary map of values that, when changed, will regenerate the key of this generator.",
+						},
+						"rotation_period": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validateDuration,
+							DiffSuppressFunc: diffSuppressMatchingDurationStrings,
+							Description:      "Generate a new key on the first apply after the current one is older than this duration. Keys of a rotating generator are added to the set with the key ID `<kid>-<unix timestamp>`, instead of replacing the set.",
+						},
+						"retain_previous": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntAtLeast(0),
+							Description:  "Number of previously generated keys of a rotating generator which are kept in the set, so that tokens signed before the rotation can still be verified. Older keys are deleted.",
 						},
 					},
 				},
 			},
 		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffJWKs("key"),
+			customizeDiffJWKsFromPEM("key", "private_key_pem"),
+			customizeDiffJWKSKeyOrder,
+			customizeDiffJWKSGenerators,
+			customizeDiffJWKSJSON,
+			customizeDiffJWKSPublicOutputs,
+		),
 		CreateContext: createJWKSResource,
 		ReadContext:   readJWKSResource,
 		UpdateContext: updateJWKSResource,
@@ -77,18 +126,13 @@ func createJWKSResource(ctx context.Context, data *schema.ResourceData, meta int
 }
 
 func generateJWKSResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	hydraClient := meta.(*ClientConfig).hydraClient
-
 	setName := data.Get("name").(string)
-	generators := data.Get("generator").([]interface{})
-	generator := generators[0].(map[string]interface{})
+	now := time.Now()
 
-	err := retryThrottledHydraAction(func() (*http.Response, error) {
-		_, resp, err := hydraClient.JwkApi.CreateJsonWebKeySet(ctx, setName).CreateJsonWebKeySet(*dataToJWKGeneratorRequest(generator)).Execute()
-		return resp, err
-	}, meta.(*ClientConfig).backOff)
-	if err != nil {
-		return diag.FromErr(err)
+	for _, generator := range data.Get("generator").([]interface{}) {
+		if err := generateJWK(ctx, meta, setName, dataToJWKGeneratorRequest(generator.(map[string]interface{}), now)); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	data.SetId(setName)
@@ -110,18 +154,48 @@ func readJWKSResource(ctx context.Context, data *schema.ResourceData, meta inter
 		return diag.FromErr(err)
 	}
 
-	dataFromJWKS(data, jsonWebKeySet, "key")
+	if jwksJSON, ok := data.GetOk("jwks_json"); ok {
+		// Keep the configured document if Hydra merely returns the keys in a different order or form.
+		current, err := json.Marshal(jsonWebKeySet)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !diffSuppressEquivalentJWKSJSON("jwks_json", jwksJSON.(string), string(current), data) {
+			data.Set("jwks_json", string(current))
+		}
+	}
 
-	return nil
+	return diag.FromErr(dataFromPublicJWKS(data, jsonWebKeySet, "key", "private_key_pem", "certificate_chain_pem"))
 }
 
 func updateJWKSResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// Generated keys are never uploaded, they can only be regenerated.
+	if _, ok := data.GetOk("generator"); ok {
+		return reconcileJWKSGenerators(ctx, data, meta)
+	}
+	// Removing the last generator only deletes its keys, unless the set is replaced by configured keys.
+	if oldGenerators, _ := data.GetChange("generator"); len(oldGenerators.([]interface{})) > 0 &&
+		!isConfigured(data, "key") && !isConfigured(data, "jwks_json") {
+		return reconcileJWKSGenerators(ctx, data, meta)
+	}
+
 	hydraClient := meta.(*ClientConfig).hydraClient
 
 	setName := data.Get("name").(string)
 
-	err := retryThrottledHydraAction(func() (*http.Response, error) {
-		_, resp, err := hydraClient.JwkApi.SetJsonWebKeySet(ctx, setName).JsonWebKeySet(*dataToJWKS(data, "key")).Execute()
+	jsonWebKeySet, err := dataToJWKS(data, "key")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if jwksJSON, ok := data.GetOk("jwks_json"); ok {
+		jsonWebKeySet, err = parseJWKSJSON(jwksJSON.(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		_, resp, err := hydraClient.JwkApi.SetJsonWebKeySet(ctx, setName).JsonWebKeySet(*jsonWebKeySet).Execute()
 		return resp, err
 	}, meta.(*ClientConfig).backOff)
 	if err != nil {
@@ -148,27 +222,487 @@ func deleteJWKSResource(ctx context.Context, data *schema.ResourceData, meta int
 	return nil
 }
 
-func dataToJWKGeneratorRequest(data map[string]interface{}) *hydra.CreateJsonWebKeySet {
+// reconcileJWKSGenerators generates the keys of new and changed generators and rotates the keys of rotating generators.
+// Keys of removed generators and keys exceeding retain_previous are deleted, other keys of the set are left alone.
+func reconcileJWKSGenerators(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	setName := data.Get("name").(string)
+	now := time.Now()
+
+	var jsonWebKeySet *hydra.JsonWebKeySet
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		var resp *http.Response
+		jsonWebKeySet, resp, err = hydraClient.JwkApi.GetJsonWebKeySet(ctx, setName).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	kids := make([]string, len(jsonWebKeySet.Keys))
+	for i, jwk := range jsonWebKeySet.Keys {
+		kids[i] = jwk.Kid
+	}
+
+	oldGenerators, newGenerators := data.GetChange("generator")
+
+	previous := make(map[string]map[string]interface{})
+	for _, g := range oldGenerators.([]interface{}) {
+		generator := g.(map[string]interface{})
+		previous[generator["kid"].(string)] = generator
+	}
+
+	for _, g := range newGenerators.([]interface{}) {
+		generator := g.(map[string]interface{})
+		kid := generator["kid"].(string)
+
+		// Keys of generators which aren't in the state yet, e.g. after an import, are adopted.
+		old, ok := previous[kid]
+		regenerate := ok && isJWKGeneratorChanged(old, generator)
+		delete(previous, kid)
+
+		rotated := rotatedJWKs(kid, kids)
+
+		if generator["rotation_period"].(string) == "" {
+			// A generator without rotation owns a single key with exactly its kid, keys left over from rotation are deleted.
+			keep := !regenerate && slices.ContainsFunc(rotated, func(key rotatedJWK) bool { return key.kid == kid })
+			for _, key := range rotated {
+				if keep && key.kid == kid {
+					continue
+				}
+				if err := deleteJWK(ctx, meta, setName, key.kid); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+			if !keep {
+				if err := generateJWK(ctx, meta, setName, dataToJWKGeneratorRequest(generator, now)); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+			continue
+		}
+
+		if regenerate || isJWKRotationDue(generator, rotated, now) {
+			request := dataToJWKGeneratorRequest(generator, now)
+			if err := generateJWK(ctx, meta, setName, request); err != nil {
+				return diag.FromErr(err)
+			}
+			rotated = append([]rotatedJWK{{kid: request.Kid, generatedAt: now}}, rotated...)
+		}
+
+		for _, key := range expiredJWKs(generator, rotated) {
+			if err := deleteJWK(ctx, meta, setName, key.kid); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	for kid := range previous {
+		for _, key := range rotatedJWKs(kid, kids) {
+			if err := deleteJWK(ctx, meta, setName, key.kid); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	return readJWKSResource(ctx, data, meta)
+}
+
+func generateJWK(ctx context.Context, meta interface{}, setName string, request *hydra.CreateJsonWebKeySet) error {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	return retryThrottledHydraAction(func() (*http.Response, error) {
+		_, resp, err := hydraClient.JwkApi.CreateJsonWebKeySet(ctx, setName).CreateJsonWebKeySet(*request).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+}
+
+func deleteJWK(ctx context.Context, meta interface{}, setName, kid string) error {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	return retryThrottledHydraAction(func() (*http.Response, error) {
+		return hydraClient.JwkApi.DeleteJsonWebKey(ctx, setName, kid).Execute()
+	}, meta.(*ClientConfig).backOff)
+}
+
+// isJWKGeneratorChanged reports whether the key of a generator has to be regenerated.
+func isJWKGeneratorChanged(old, new map[string]interface{}) bool {
+	return old["alg"] != new["alg"] || old["use"] != new["use"] || !reflect.DeepEqual(old["keepers"], new["keepers"])
+}
+
+// customizeDiffJWKSGenerators rejects generators sharing a kid, and plans an update of the keys
+// once generators change, a rotating generator is due or holds more keys than it retains.
+func customizeDiffJWKSGenerators(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	generators := d.Get("generator").([]interface{})
+
+	seen := make(map[string]bool)
+	for _, g := range generators {
+		generator := g.(map[string]interface{})
+		if err := validateJWKGenerator(generator); err != nil {
+			return err
+		}
+
+		kid := generator["kid"].(string)
+		if seen[kid] {
+			return fmt.Errorf("duplicate generator kid %q", kid)
+		}
+		seen[kid] = true
+	}
+
+	// Keys of rotating generators get the kid of the generator suffixed with the time they were generated at,
+	// so a generator with such a kid would claim the keys of another generator as its own.
+	for _, g := range generators {
+		kid := g.(map[string]interface{})["kid"].(string)
+		for _, other := range generators {
+			otherKid := other.(map[string]interface{})["kid"].(string)
+			if _, ok := rotatedJWKGeneratedAt(kid, otherKid); ok {
+				return fmt.Errorf("generator kid %q collides with the rotated keys of generator kid %q", otherKid, kid)
+			}
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("generator") {
+		return d.SetNewComputed("key")
+	}
+
+	var kids []string
+	oldKeys, _ := d.GetChange("key")
+	for _, key := range oldKeys.([]interface{}) {
+		if key != nil {
+			kids = append(kids, key.(map[string]interface{})["kid"].(string))
+		}
+	}
+
+	now := time.Now()
+	for _, g := range generators {
+		generator := g.(map[string]interface{})
+		if generator["rotation_period"].(string) == "" {
+			continue
+		}
+
+		rotated := rotatedJWKs(generator["kid"].(string), kids)
+		if isJWKRotationDue(generator, rotated, now) || len(expiredJWKs(generator, rotated)) > 0 {
+			return d.SetNewComputed("key")
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffJWKSKeyOrder keeps the keys in state if the configured keys only changed their order.
+// Keys are matched by kid like the keys read from Hydra, so reordering the key blocks doesn't update the set.
+func customizeDiffJWKSKeyOrder(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("key") {
+		return nil
+	}
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return nil
+	}
+	configuredKeys := rawConfig.GetAttr("key")
+	if configuredKeys.IsNull() || !configuredKeys.IsKnown() {
+		return nil
+	}
+
+	oldKeys, _ := d.GetChange("key")
+	prior := make(map[string]map[string]interface{})
+	for _, k := range oldKeys.([]interface{}) {
+		if k == nil {
+			return nil
+		}
+		key := k.(map[string]interface{})
+		prior[key["kid"].(string)] = key
+	}
+	if len(prior) != len(oldKeys.([]interface{})) || len(prior) != configuredKeys.LengthInt() {
+		return nil
+	}
+
+	for _, v := range configuredKeys.AsValueSlice() {
+		config, ok := jwkDataFromConfig(v)
+		if !ok {
+			return nil
+		}
+		jwk, err := dataToJWK(config)
+		if err != nil {
+			return nil
+		}
+
+		key, ok := prior[jwk.Kid]
+		if !ok || config["private_key_pem"] != key["private_key_pem"] || config["certificate_chain_pem"] != key["certificate_chain_pem"] {
+			return nil
+		}
+		priorJWK, err := dataToJWK(key)
+		if err != nil || !reflect.DeepEqual(jwk, priorJWK) {
+			return nil
+		}
+		delete(prior, jwk.Kid)
+	}
+
+	return d.SetNew("key", oldKeys)
+}
+
+// customizeDiffJWKSJSON plans an update of the keys once the keys of jwks_json change.
+// HasChange doesn't take DiffSuppressFunc into account, so the documents are compared by their keys.
+func customizeDiffJWKSJSON(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("jwks_json") {
+		return nil
+	}
+	if !d.NewValueKnown("jwks_json") {
+		return d.SetNewComputed("key")
+	}
+
+	// Removing the document leaves the keys of the set as they are.
+	old, new := d.GetChange("jwks_json")
+	if new.(string) == "" {
+		return nil
+	}
+	if !diffSuppressEquivalentJWKSJSON("jwks_json", old.(string), new.(string), nil) {
+		return d.SetNewComputed("key")
+	}
+	return nil
+}
+
+// customizeDiffJWKSPublicOutputs marks public_jwks_json as unknown whenever the keys are going to change.
+func customizeDiffJWKSPublicOutputs(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChanges("key", "generator") {
+		return d.SetNewComputed("public_jwks_json")
+	}
+	return nil
+}
+
+// rotatedJWK is a key generated by a rotating generator.
+type rotatedJWK struct {
+	kid         string
+	generatedAt time.Time
+}
+
+// rotatedJWKs returns the keys generated by the rotating generator with kid, newest first.
+// A key with exactly kid was generated before rotation was enabled, it's considered the oldest one.
+func rotatedJWKs(kid string, kids []string) []rotatedJWK {
+	var rotated []rotatedJWK
+	for _, k := range kids {
+		if k == kid {
+			rotated = append(rotated, rotatedJWK{kid: k})
+			continue
+		}
+
+		if generatedAt, ok := rotatedJWKGeneratedAt(kid, k); ok {
+			rotated = append(rotated, rotatedJWK{kid: k, generatedAt: generatedAt})
+		}
+	}
+
+	sort.Slice(rotated, func(i, j int) bool {
+		return rotated[i].generatedAt.After(rotated[j].generatedAt)
+	})
+	return rotated
+}
+
+// rotatedJWKGeneratedAt parses the time a key of the rotating generator with kid was generated at from the key's kid.
+func rotatedJWKGeneratedAt(kid, rotatedKid string) (time.Time, bool) {
+	timestamp, ok := strings.CutPrefix(rotatedKid, kid+"-")
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func isJWKRotationDue(generator map[string]interface{}, rotated []rotatedJWK, now time.Time) bool {
+	if len(rotated) == 0 {
+		return true
+	}
+
+	// The period is validated at plan time.
+	period, _ := time.ParseDuration(generator["rotation_period"].(string))
+	return !now.Before(rotated[0].generatedAt.Add(period))
+}
+
+// expiredJWKs returns the keys beyond the current one and the retained previous ones.
+func expiredJWKs(generator map[string]interface{}, rotated []rotatedJWK) []rotatedJWK {
+	retain := generator["retain_previous"].(int) + 1
+	if len(rotated) <= retain {
+		return nil
+	}
+	return rotated[retain:]
+}
+
+func dataToJWKGeneratorRequest(data map[string]interface{}, now time.Time) *hydra.CreateJsonWebKeySet {
+	kid := data["kid"].(string)
+	if rotationPeriod, _ := data["rotation_period"].(string); rotationPeriod != "" {
+		kid = fmt.Sprintf("%s-%d", kid, now.Unix())
+	}
+
 	return hydra.NewCreateJsonWebKeySet(
 		data["alg"].(string),
-		data["kid"].(string),
+		kid,
 		data["use"].(string),
 	)
 }
 
-func dataToJWKS(data *schema.ResourceData, key string) *hydra.JsonWebKeySet {
+// decodeJWKSJSON decodes a JSON Web Key Set document. Members which the API doesn't support are rejected, Hydra would drop them.
+func decodeJWKSJSON(document string) (*hydra.JsonWebKeySet, error) {
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.DisallowUnknownFields()
+
 	jwks := &hydra.JsonWebKeySet{}
-	for _, jwkData := range data.Get(key).([]interface{}) {
-		jwk := dataToJWK(jwkData.(map[string]interface{}))
-		jwks.Keys = append(jwks.Keys, *jwk)
+	if err := decoder.Decode(jwks); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON Web Key Set")
 	}
-	return jwks
+
+	// An empty x5c is the same as none, Hydra omits it.
+	for i := range jwks.Keys {
+		if len(jwks.Keys[i].X5c) == 0 {
+			jwks.Keys[i].X5c = nil
+		}
+	}
+	return jwks, nil
 }
 
-func dataFromJWKS(data *schema.ResourceData, jwks *hydra.JsonWebKeySet, key string) {
-	keys := make([]map[string]interface{}, len(jwks.Keys))
+// parseJWKSJSON decodes a JSON Web Key Set document and validates its keys like key blocks.
+func parseJWKSJSON(document string) (*hydra.JsonWebKeySet, error) {
+	jwks, err := decodeJWKSJSON(document)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(jwks.Keys))
 	for i, jwk := range jwks.Keys {
+		if jwk.Kid == "" {
+			return nil, fmt.Errorf("key %d: kid is required", i)
+		}
+		if seen[jwk.Kid] {
+			return nil, fmt.Errorf("key %d: duplicate kid %q", i, jwk.Kid)
+		}
+		seen[jwk.Kid] = true
+
+		if _, ok := jwkAlgorithms[jwk.Alg]; !ok {
+			return nil, fmt.Errorf("key %q: unsupported alg %q", jwk.Kid, jwk.Alg)
+		}
+		if err := validateJWK(&jwk); err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+	}
+	return jwks, nil
+}
+
+func validateJWKSJSON(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+
+	if _, err := parseJWKSJSON(v); err != nil {
+		errors = append(errors, fmt.Errorf("%q is not a valid JSON Web Key Set: %s", key, err))
+	}
+	return
+}
+
+// normalizeJWKSJSON returns a JSON Web Key Set document in the form Hydra returns it.
+// Invalid documents are returned unchanged to be reported by validation.
+func normalizeJWKSJSON(val interface{}) string {
+	document := val.(string)
+
+	jwks, err := decodeJWKSJSON(document)
+	if err != nil {
+		return document
+	}
+
+	normalized, err := json.Marshal(jwks)
+	if err != nil {
+		return document
+	}
+	return string(normalized)
+}
+
+// diffSuppressEquivalentJWKSJSON compares the keys of two JSON Web Key Set documents by kid, regardless of their order and formatting.
+func diffSuppressEquivalentJWKSJSON(k, old, new string, d *schema.ResourceData) bool {
+	oldJWKS, err := decodeJWKSJSON(old)
+	if err != nil {
+		return false
+	}
+	newJWKS, err := decodeJWKSJSON(new)
+	if err != nil {
+		return false
+	}
+	if len(oldJWKS.Keys) != len(newJWKS.Keys) {
+		return false
+	}
+
+	oldKeys := make(map[string]hydra.JsonWebKey, len(oldJWKS.Keys))
+	for _, jwk := range oldJWKS.Keys {
+		oldKeys[jwk.Kid] = jwk
+	}
+	for _, jwk := range newJWKS.Keys {
+		if oldJWK, ok := oldKeys[jwk.Kid]; !ok || !reflect.DeepEqual(oldJWK, jwk) {
+			return false
+		}
+	}
+	return true
+}
+
+func dataToJWKS(data *schema.ResourceData, key string) (*hydra.JsonWebKeySet, error) {
+	jwks := &hydra.JsonWebKeySet{}
+	for i, jwkData := range data.Get(key).([]interface{}) {
+		jwk, err := dataToJWK(jwkData.(map[string]interface{}))
+		if err != nil {
+			return nil, fmt.Errorf("%s.%d: %w", key, i, err)
+		}
+		jwks.Keys = append(jwks.Keys, *jwk)
+	}
+	return jwks, nil
+}
+
+func dataFromJWKS(data *schema.ResourceData, jwks *hydra.JsonWebKeySet, key string, inputs ...string) error {
+	prior := data.Get(key).([]interface{})
+	ordered := orderJWKs(prior, jwks.Keys)
+
+	keys := make([]map[string]interface{}, len(ordered))
+	for i, jwk := range ordered {
 		keys[i] = dataFromJWK(&jwk)
 	}
+	mergeJWKInputs(prior, keys, inputs...)
+	return data.Set(key, keys)
+}
+
+// dataFromPublicJWKS is dataFromJWKS for key sets which expose their public keys, as public_jwks_json
+// and as the thumbprint and PEM encoded public key of each key.
+func dataFromPublicJWKS(data *schema.ResourceData, jwks *hydra.JsonWebKeySet, key string, inputs ...string) error {
+	prior := data.Get(key).([]interface{})
+	ordered := orderJWKs(prior, jwks.Keys)
+	publicJWKS := hydra.JsonWebKeySet{Keys: []hydra.JsonWebKey{}}
+
+	keys := make([]map[string]interface{}, len(ordered))
+	for i, jwk := range ordered {
+		keys[i] = dataFromJWK(&jwk)
+		// Keys which can't be converted, e.g. Ed448 keys, are left without a thumbprint or PEM encoding.
+		keys[i]["thumbprint_sha256"], _ = jwkThumbprint(&jwk)
+		keys[i]["public_key_pem"], _ = jwkPublicKeyPEM(&jwk)
+
+		if public, ok := publicJWK(jwk); ok {
+			publicJWKS.Keys = append(publicJWKS.Keys, public)
+		}
+	}
+	mergeJWKInputs(prior, keys, inputs...)
+
+	publicJWKSJSON, err := json.Marshal(publicJWKS)
+	if err != nil {
+		return err
+	}
+
 	data.Set(key, keys)
+	data.Set("public_jwks_json", string(publicJWKSJSON))
+	return nil
 }
@@ -1,9 +1,24 @@
 package provider
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccResourceJWKS_Generated(t *testing.T) {
@@ -46,6 +61,544 @@ func TestAccResourceJWKS_Inlined(t *testing.T) {
 	})
 }
 
+// jwksStub keeps the keys of a single set in memory, like the key set endpoints of the Admin API.
+type jwksStub struct {
+	sync.Mutex
+	keys      []map[string]interface{}
+	generated int
+}
+
+func (s *jwksStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.Split(strings.TrimPrefix(req.URL.Path, "/admin/keys/"), "/")
+	switch {
+	case req.Method == http.MethodPost:
+		var generate map[string]interface{}
+		_ = json.NewDecoder(req.Body).Decode(&generate)
+		s.generated++
+		key := map[string]interface{}{"alg": generate["alg"], "kid": generate["kid"], "use": generate["use"], "kty": "RSA", "e": "AQAB", "n": fmt.Sprint(s.generated)}
+		s.keys = append(s.keys, key)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{key}})
+	case req.Method == http.MethodPut:
+		var set struct {
+			Keys []map[string]interface{} `json:"keys"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&set)
+		s.keys = set.Keys
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	case req.Method == http.MethodDelete && len(path) == 2:
+		for i, key := range s.keys {
+			if key["kid"] == path[1] {
+				s.keys = append(s.keys[:i], s.keys[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodDelete:
+		s.keys = nil
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+	}
+}
+
+// age moves the generation time of the rotated keys back by d.
+func (s *jwksStub) age(t *testing.T, d time.Duration) {
+	for _, key := range s.keys {
+		kid := key["kid"].(string)
+		rotated := rotatedJWKs("signing", []string{kid})
+		require.Len(t, rotated, 1)
+		key["kid"] = fmt.Sprintf("signing-%d", rotated[0].generatedAt.Add(-d).Unix())
+	}
+}
+
+func TestResourceJWKS_rotation(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "rotated",
+		"generator": []interface{}{
+			map[string]interface{}{
+				"alg":             "RS256",
+				"kid":             "signing",
+				"use":             "sig",
+				"keepers":         map[string]interface{}{"version": "1"},
+				"rotation_period": "24h",
+				"retain_previous": 1,
+			},
+		},
+	})
+
+	// plan refreshes the state and returns the planned changes, like terraform plan.
+	plan := func(t *testing.T, state *terraform.InstanceState) (*terraform.InstanceState, *terraform.InstanceDiff) {
+		state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		diff, err := r.Diff(ctx, state, config, meta)
+		require.NoError(t, err)
+		return state, diff
+	}
+
+	apply := func(t *testing.T, state *terraform.InstanceState, diff *terraform.InstanceDiff) *terraform.InstanceState {
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	diff, err := r.Diff(ctx, nil, config, meta)
+	require.NoError(t, err)
+	state := apply(t, nil, diff)
+
+	require.Len(t, stub.keys, 1)
+	require.Equal(t, "1", state.Attributes["key.#"])
+	require.Regexp(t, `^signing-\d+$`, state.Attributes["key.0.kid"])
+
+	t.Run("case=keeps the key within the rotation period", func(t *testing.T) {
+		stub.age(t, 23*time.Hour)
+
+		var diff *terraform.InstanceDiff
+		state, diff = plan(t, state)
+		require.Nil(t, diff)
+	})
+
+	t.Run("case=adds a new key once the rotation period passed", func(t *testing.T) {
+		stub.age(t, time.Hour)
+		previous := stub.keys[0]["kid"]
+
+		var diff *terraform.InstanceDiff
+		state, diff = plan(t, state)
+		state = apply(t, state, diff)
+
+		require.Len(t, stub.keys, 2)
+		require.Equal(t, "2", state.Attributes["key.#"])
+		require.Equal(t, previous, stub.keys[0]["kid"])
+	})
+
+	t.Run("case=deletes keys beyond the retained ones", func(t *testing.T) {
+		stub.age(t, 24*time.Hour)
+		previous := stub.keys[1]["kid"]
+
+		var diff *terraform.InstanceDiff
+		state, diff = plan(t, state)
+		state = apply(t, state, diff)
+
+		require.Len(t, stub.keys, 2)
+		require.Equal(t, "2", state.Attributes["key.#"])
+		require.Equal(t, previous, stub.keys[0]["kid"])
+	})
+
+	t.Run("case=deletes the set", func(t *testing.T) {
+		state.Attributes["id"] = state.ID
+		_, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Empty(t, stub.keys)
+	})
+}
+
+func TestResourceJWKS_generators(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	generator := func(alg, kid, use, version string) map[string]interface{} {
+		return map[string]interface{}{"alg": alg, "kid": kid, "use": use, "keepers": map[string]interface{}{"version": version}}
+	}
+
+	apply := func(t *testing.T, state *terraform.InstanceState, generators ...interface{}) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "id-token", "generator": generators}), meta)
+		require.NoError(t, err)
+		require.NotNil(t, diff)
+		require.False(t, diff.RequiresNew())
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	keys := func() map[string]string {
+		keys := make(map[string]string)
+		for _, key := range stub.keys {
+			keys[key["kid"].(string)] = key["n"].(string)
+		}
+		return keys
+	}
+
+	state := apply(t, nil, generator("RS256", "rs", "sig", "1"), generator("ES256", "es", "sig", "1"))
+	require.Equal(t, map[string]string{"rs": "1", "es": "2"}, keys())
+	require.Equal(t, "2", state.Attributes["key.#"])
+
+	t.Run("case=regenerates only the key whose keepers changed", func(t *testing.T) {
+		state = apply(t, state, generator("RS256", "rs", "sig", "1"), generator("ES256", "es", "sig", "2"))
+		require.Equal(t, map[string]string{"rs": "1", "es": "3"}, keys())
+	})
+
+	t.Run("case=adds and removes generators", func(t *testing.T) {
+		state = apply(t, state, generator("ES256", "es", "sig", "2"), generator("RS256", "enc", "enc", "1"))
+		require.Equal(t, map[string]string{"es": "3", "enc": "4"}, keys())
+		require.Equal(t, "2", state.Attributes["key.#"])
+	})
+
+	t.Run("case=rejects generators sharing a kid", func(t *testing.T) {
+		_, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":      "id-token",
+			"generator": []interface{}{generator("ES256", "es", "sig", "2"), generator("RS256", "es", "sig", "1")},
+		}), meta)
+		require.ErrorContains(t, err, `duplicate generator kid "es"`)
+	})
+}
+
+func TestResourceJWKS_removeGenerators(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{keys: []map[string]interface{}{{"alg": "RS256", "kid": "other", "use": "sig", "kty": "RSA", "e": "AQAB", "n": "0"}}}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	apply := func(t *testing.T, state *terraform.InstanceState, config map[string]interface{}) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+		require.NotNil(t, diff)
+		require.False(t, diff.RequiresNew())
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	state := apply(t, nil, map[string]interface{}{
+		"name":      "id-token",
+		"generator": []interface{}{map[string]interface{}{"alg": "RS256", "kid": "rs", "use": "sig", "keepers": map[string]interface{}{"version": "1"}}},
+	})
+	require.Len(t, stub.keys, 2)
+
+	// Without any generator left the keys of the removed ones are deleted, instead of replacing the set with no keys.
+	state = apply(t, state, map[string]interface{}{"name": "id-token"})
+	require.Len(t, stub.keys, 1)
+	require.Equal(t, "other", stub.keys[0]["kid"])
+	require.Equal(t, "1", state.Attributes["key.#"])
+	require.Equal(t, "other", state.Attributes["key.0.kid"])
+}
+
+func TestResourceJWKS_keyConflictsWithGenerator(t *testing.T) {
+	diags := resourceJWKS().Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":      "id-token",
+		"key":       []interface{}{map[string]interface{}{"alg": "RS256", "kid": "rs", "use": "sig", "kty": "RSA", "e": "AQAB", "n": "0"}},
+		"generator": []interface{}{map[string]interface{}{"alg": "ES256", "kid": "es", "use": "sig", "keepers": map[string]interface{}{"version": "1"}}},
+	}))
+	require.True(t, diags.HasError())
+	require.Contains(t, diags[0].Detail, "conflicts with generator")
+}
+
+func TestResourceJWKS_generatorAlgorithms(t *testing.T) {
+	r := resourceJWKS()
+
+	// Only the algorithms documented for CreateJsonWebKeySet are accepted, the others would only fail at apply.
+	for alg, valid := range map[string]bool{
+		"RS256":        true,
+		"ES256":        true,
+		"ES512":        true,
+		"HS256":        true,
+		"HS512":        true,
+		"RS384":        false,
+		"RS512":        false,
+		"PS256":        false,
+		"ES384":        false,
+		"EdDSA":        false,
+		"HS384":        false,
+		"RSA-OAEP":     false,
+		"RSA-OAEP-256": false,
+	} {
+		t.Run("case="+alg, func(t *testing.T) {
+			diags := r.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+				"name":      "generated",
+				"generator": []interface{}{map[string]interface{}{"alg": alg, "kid": "generated", "use": "sig", "keepers": map[string]interface{}{"version": "1"}}},
+			}))
+			require.Equal(t, valid, !diags.HasError(), "%v", diags)
+		})
+	}
+}
+
+func TestResourceJWKS_generatorKids(t *testing.T) {
+	r := resourceJWKS()
+
+	for name, tc := range map[string]struct {
+		kids []string
+		err  string
+	}{
+		"distinct":           {kids: []string{"sig", "enc", "sig-next"}},
+		"rotated key of sig": {kids: []string{"sig", "sig-1"}, err: `generator kid "sig-1" collides with the rotated keys of generator kid "sig"`},
+		"in any order":       {kids: []string{"sig-1700000000", "sig"}, err: `generator kid "sig-1700000000" collides`},
+	} {
+		t.Run("case="+name, func(t *testing.T) {
+			generators := make([]interface{}, len(tc.kids))
+			for i, kid := range tc.kids {
+				generators[i] = map[string]interface{}{"alg": "ES256", "kid": kid, "use": "sig"}
+			}
+
+			_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "id-token", "generator": generators}), nil)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestResourceJWKS_privateKeyPEM(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	newKey := func(t *testing.T) (string, string) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+
+		jwk := &hydra.JsonWebKey{}
+		require.NoError(t, setJWKFromPrivateKeyPEM(jwk, privateKeyPEM, ""))
+		return privateKeyPEM, jwk.Kid
+	}
+
+	plan := func(t *testing.T, state *terraform.InstanceState, privateKeyPEM string) (*terraform.InstanceState, *terraform.InstanceDiff) {
+		config, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("imported"),
+			"key": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"alg":             cty.StringVal("RS256"),
+				"use":             cty.StringVal("sig"),
+				"private_key_pem": cty.StringVal(privateKeyPEM),
+			})}),
+		}))
+		require.NoError(t, err)
+
+		if state == nil {
+			state = &terraform.InstanceState{}
+		}
+		state.RawConfig = config
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), meta)
+		require.NoError(t, err)
+		return state, diff
+	}
+
+	apply := func(t *testing.T, state *terraform.InstanceState, diff *terraform.InstanceDiff) *terraform.InstanceState {
+		if state.ID == "" {
+			state = nil
+		}
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	privateKeyPEM, kid := newKey(t)
+	state, diff := plan(t, nil, privateKeyPEM)
+	state = apply(t, state, diff)
+
+	require.Len(t, stub.keys, 1)
+	require.Equal(t, kid, stub.keys[0]["kid"])
+	require.Equal(t, "RSA", stub.keys[0]["kty"])
+	require.NotEmpty(t, stub.keys[0]["d"])
+	require.NotEmpty(t, stub.keys[0]["qi"])
+	require.Equal(t, kid, state.Attributes["key.0.kid"])
+
+	t.Run("case=keeps the PEM input in state", func(t *testing.T) {
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, privateKeyPEM, refreshed.Attributes["key.0.private_key_pem"])
+
+		_, diff := plan(t, refreshed, privateKeyPEM)
+		require.Nil(t, diff)
+	})
+
+	t.Run("case=plans the thumbprint of a replaced key", func(t *testing.T) {
+		privateKeyPEM, kid := newKey(t)
+
+		var diff *terraform.InstanceDiff
+		state, diff = plan(t, state, privateKeyPEM)
+		require.Equal(t, kid, diff.Attributes["key.0.kid"].New)
+
+		state = apply(t, state, diff)
+		require.Len(t, stub.keys, 1)
+		require.Equal(t, kid, stub.keys[0]["kid"])
+		require.Equal(t, kid, state.Attributes["key.0.kid"])
+	})
+}
+
+func TestResourceJWKS_keyOrder(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	signing := map[string]cty.Value{"kty": cty.StringVal("RSA"), "kid": cty.StringVal("signing"), "alg": cty.StringVal("RS256"), "use": cty.StringVal("sig"), "e": cty.StringVal("AQAB"), "n": cty.StringVal(rfc7638N)}
+	encryption := map[string]cty.Value{"kty": cty.StringVal("oct"), "kid": cty.StringVal("encryption"), "alg": cty.StringVal("A256KW"), "use": cty.StringVal("enc"), "k": cty.StringVal("GawgguFyGrWKav7AX4VKUg")}
+
+	plan := func(t *testing.T, state *terraform.InstanceState, keys ...map[string]cty.Value) (*terraform.InstanceState, *terraform.InstanceDiff) {
+		configuredKeys := make([]cty.Value, len(keys))
+		for i, key := range keys {
+			configuredKeys[i] = cty.ObjectVal(key)
+		}
+		config, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+			"name": cty.StringVal("ordered"),
+			"key":  cty.TupleVal(configuredKeys),
+		}))
+		require.NoError(t, err)
+
+		if state == nil {
+			state = &terraform.InstanceState{}
+		}
+		state.RawConfig = config
+
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), meta)
+		require.NoError(t, err)
+		return state, diff
+	}
+
+	_, diff := plan(t, nil, signing, encryption)
+	state, diags := r.Apply(ctx, nil, diff, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Len(t, stub.keys, 2)
+	require.Equal(t, "signing", state.Attributes["key.0.kid"])
+
+	t.Run("case=keeps the order of keys read from Hydra", func(t *testing.T) {
+		stub.keys[0], stub.keys[1] = stub.keys[1], stub.keys[0]
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, state.Attributes, refreshed.Attributes)
+	})
+
+	t.Run("case=ignores reordered key blocks", func(t *testing.T) {
+		_, diff := plan(t, state, encryption, signing)
+		require.Nil(t, diff)
+	})
+
+	t.Run("case=updates reordered keys which changed", func(t *testing.T) {
+		changed := make(map[string]cty.Value)
+		for member, value := range encryption {
+			changed[member] = value
+		}
+		changed["k"] = cty.StringVal("hJtXIZ2uSN5kbQfbtTNWbg")
+
+		_, diff := plan(t, state, changed, signing)
+		require.NotNil(t, diff)
+		require.Equal(t, "hJtXIZ2uSN5kbQfbtTNWbg", diff.Attributes["key.0.k"].New)
+	})
+}
+
+func TestResourceJWKS_jwksJSON(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &jwksStub{}
+	meta := newHydraStub(t, stub)
+
+	r := resourceJWKS()
+
+	apply := func(t *testing.T, state *terraform.InstanceState, jwksJSON string) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{"name": "ceremony", "jwks_json": jwksJSON}), meta)
+		require.NoError(t, err)
+		if diff == nil {
+			return state
+		}
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	signing := `{"kty": "RSA", "kid": "signing", "alg": "RS256", "use": "sig", "e": "AQAB", "n": "` + rfc7638N + `"}`
+	encryption := `{"kty": "oct", "kid": "encryption", "alg": "A256KW", "use": "enc", "k": "GawgguFyGrWKav7AX4VKUg"}`
+
+	state := apply(t, nil, `{"keys": [`+signing+`, `+encryption+`]}`)
+	require.Len(t, stub.keys, 2)
+	require.Equal(t, "signing", stub.keys[0]["kid"])
+	require.Equal(t, "2", state.Attributes["key.#"])
+
+	t.Run("case=ignores the order and formatting of keys", func(t *testing.T) {
+		stub.keys[0], stub.keys[1] = stub.keys[1], stub.keys[0]
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, state.Attributes["jwks_json"], refreshed.Attributes["jwks_json"])
+
+		state = apply(t, refreshed, "{\n  \"keys\": [\n    "+encryption+",\n    "+signing+"\n  ]\n}")
+		require.Equal(t, "encryption", stub.keys[0]["kid"])
+	})
+
+	t.Run("case=replaces the keys of the set", func(t *testing.T) {
+		state = apply(t, state, `{"keys": [`+signing+`]}`)
+		require.Len(t, stub.keys, 1)
+		require.Equal(t, "signing", stub.keys[0]["kid"])
+		require.Equal(t, "1", state.Attributes["key.#"])
+	})
+
+	t.Run("case=detects drift", func(t *testing.T) {
+		stub.keys[0]["use"] = "enc"
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.NotEqual(t, state.Attributes["jwks_json"], refreshed.Attributes["jwks_json"])
+
+		state = apply(t, refreshed, `{"keys": [`+signing+`]}`)
+		require.Equal(t, "sig", stub.keys[0]["use"])
+	})
+}
+
+func TestParseJWKSJSON(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{name: "valid", document: `{"keys": [{"kty": "oct", "kid": "a", "alg": "HS256", "use": "sig", "k": "c2VjcmV0"}]}`},
+		{name: "empty", document: `{"keys": []}`},
+		{name: "missing kid", document: `{"keys": [{"kty": "oct", "alg": "HS256", "use": "sig", "k": "c2VjcmV0"}]}`, err: "kid is required"},
+		{name: "duplicate kid", document: `{"keys": [{"kty": "oct", "kid": "a", "alg": "HS256", "use": "sig", "k": "c2VjcmV0"}, {"kty": "oct", "kid": "a", "alg": "HS512", "use": "sig", "k": "c2VjcmV0"}]}`, err: `duplicate kid "a"`},
+		{name: "unsupported alg", document: `{"keys": [{"kty": "oct", "kid": "a", "alg": "none", "use": "sig", "k": "c2VjcmV0"}]}`, err: `unsupported alg "none"`},
+		{name: "invalid key", document: `{"keys": [{"kty": "oct", "kid": "a", "alg": "RS256", "use": "sig", "k": "c2VjcmV0"}]}`, err: `key "a": alg "RS256" requires kty`},
+		{name: "unsupported member", document: `{"keys": [{"kty": "oct", "kid": "a", "alg": "HS256", "use": "sig", "k": "c2VjcmV0", "x5t": "a"}]}`, err: `unknown field "x5t"`},
+		{name: "trailing data", document: `{"keys": []} {}`, err: "unexpected data"},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			_, err := parseJWKSJSON(tc.document)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestRotatedJWKs(t *testing.T) {
+	rotated := rotatedJWKs("signing", []string{"signing-100", "other-300", "signing", "signing-300", "signing-extra", "signing-200"})
+
+	kids := make([]string, len(rotated))
+	for i, key := range rotated {
+		kids[i] = key.kid
+	}
+	require.Equal(t, []string{"signing-300", "signing-200", "signing-100", "signing"}, kids)
+}
+
 const (
 	testAccResourceJWKSGeneratedConfig = `
 provider "hydra" {
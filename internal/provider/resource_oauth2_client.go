@@ -4,11 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/mitchellh/mapstructure"
@@ -16,15 +23,16 @@ import (
 )
 
 func resourceOAuth2Client() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Description: `OAuth 2.0 clients are used to perform OAuth 2.0 and OpenID Connect flows.
 Usually, OAuth 2.0 clients are generated for applications which want to consume your OAuth 2.0 or OpenID Connect capabilities.
 To manage ORY Hydra, you will need an OAuth 2.0 Client as well.
 Make sure that this endpoint is well protected and only callable by first-party components.
 `,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importOAuth2ClientResource,
 		},
+		SchemaVersion: 2,
 		Schema: map[string]*schema.Schema{
 			"access_token_strategy": {
 				Type:         schema.TypeString,
@@ -33,14 +41,14 @@ Make sure that this endpoint is well protected and only callable by first-party
 				ValidateFunc: validation.StringInSlice([]string{"jwt", "opaque"}, false),
 			},
 			"allowed_cors_origins": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
 			"audience": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
@@ -52,9 +60,10 @@ Make sure that this endpoint is well protected and only callable by first-party
 				Description: "Boolean value specifying whether the RP requires that a sid (session ID) Claim be included in the Logout Token to identify the RP session with the OP when the backchannel_logout_uri is used. If omitted, the default value is false.",
 			},
 			"backchannel_logout_uri": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "RP URL that will cause the RP to log itself out when sent a Logout Token by the OP.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateURI(false),
+				Description:  "RP URL that will cause the RP to log itself out when sent a Logout Token by the OP.",
 			},
 			"client_id": {
 				Type:         schema.TypeString,
@@ -84,13 +93,18 @@ The secret is stored using BCrypt so it is impossible to recover it. Tell your u
 The time is represented as the number of seconds from 1970-01-01T00:00:00Z as measured in UTC until the date/time of expiration.
 This feature is currently not supported and it's value will always be set to 0.`,
 			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the client's creation in RFC 3339 format.",
+			},
 			"client_uri": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "ClientURI is an URL string of a web page providing information about the client. If present, the server SHOULD display this URL to the end-user in a clickable fashion.",
 			},
 			"contacts": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
@@ -102,15 +116,17 @@ This feature is currently not supported and it's value will always be set to 0.`
 				Description: "Boolean value specifying whether the RP requires that iss (issuer) and sid (session ID) query parameters be included to identify the RP session with the OP when the `frontchannel_logout_uri` is used. If omitted, the default value is false.",
 			},
 			"frontchannel_logout_uri": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateURI(false),
 				Description: `RP URL that will cause the RP to log itself out when rendered in an iframe by the OP.
 An iss (issuer) query parameter and a sid (session ID) query parameter MAY be included by the OP to enable the RP to validate the request and to determine which of the potentially multiple sessions is to be logged out;
 if either is included, both MUST be.`,
 			},
 			"grant_types": {
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:             schema.TypeSet,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressOAuth2ClientProfileDefault,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 					ValidateFunc: validation.StringInSlice([]string{
@@ -121,14 +137,16 @@ if either is included, both MUST be.`,
 			"jwk": {
 				Type:     schema.TypeList,
 				Optional: true,
-				Elem:     resourceJWK(),
+				Computed: true,
+				Elem:     resourceClientJWK(),
 				Description: `A JSON Web Key (JWK) is a JavaScript Object Notation (JSON) data structure that represents a cryptographic key.
 A JWK Set is a JSON data structure that represents a set of JWKs.
 A JSON Web Key is identified by its set and key id. ORY Hydra uses this functionality to store cryptographic keys used for TLS and JSON Web Tokens (such as OpenID Connect ID tokens), and allows storing user-defined keys as well.`,
 			},
 			"jwks_uri": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateURI(false),
 				Description: `URL for the Client's JSON Web Key Set [JWK] document.
 If the Client signs requests to the Server, it contains the signing key(s) the Server uses to validate signatures from the Client.
 The JWK Set MAY also contain the Client's encryption keys(s), which are used by the Server to encrypt responses to the Client.
@@ -141,19 +159,14 @@ The JWK x5c parameter MAY be used to provide X.509 representations of keys provi
 				Optional:    true,
 				Description: "LogoURI is an URL string that references a logo for the client.",
 			},
-			"metadata_json": {
-				Type:          schema.TypeString,
-				Optional:      true,
-				ValidateFunc:  validation.StringIsJSON,
-				ConflictsWith: []string{"metadata"},
-			},
 			"metadata": {
-				Type:     schema.TypeMap,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				ConflictsWith: []string{"metadata_json"},
+				Type:                  schema.TypeString,
+				Optional:              true,
+				ValidateFunc:          validateClientMetadata,
+				StateFunc:             normalizeJSON,
+				DiffSuppressFunc:      diffSuppressEquivalentClientMetadata,
+				DiffSuppressOnRefresh: true,
+				Description:           "Metadata as a JSON object, usually set with `jsonencode`. Numbers, booleans and nested values keep their type, and the metadata is compared regardless of formatting and key order.",
 			},
 			"owner": {
 				Type:        schema.TypeString,
@@ -166,43 +179,65 @@ The JWK x5c parameter MAY be used to provide X.509 representations of keys provi
 				Description: "PolicyURI is a URL string that points to a human-readable privacy policy document that describes how the deployment organization collects, uses, retains, and discloses personal data.",
 			},
 			"post_logout_redirect_uris": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: validateURI(true),
 				},
 			},
+			"profile": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(oAuth2ClientProfileNames(), false),
+				Description:  "Type of application the client is used by, one of `spa`, `native`, `web` and `service`. The profile supplies defaults for `grant_types`, `response_types` and `token_endpoint_auth_method` following the OAuth 2.0 Security Best Current Practice and restricts them accordingly. Attributes set explicitly override the defaults of the profile.",
+			},
 			"redirect_uris": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: validateURI(true),
 				},
 			},
+			"registration_access_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "OpenID Connect Dynamic Client Registration access token, which can be used to get, update or delete the client through the public API. Hydra only returns it when the client is created.",
+			},
+			"registration_client_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "OpenID Connect Dynamic Client Registration URL of the client.",
+			},
 			"request_object_signing_alg": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "JWS [JWS] alg algorithm [JWA] that MUST be used for signing Request Objects sent to the OP. All Request Objects from this Client MUST be rejected, if not signed with this algorithm.",
 			},
 			"request_uris": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: validateURI(false),
 				},
 			},
 			"response_types": {
-				Type:     schema.TypeList,
-				Optional: true,
+				Type:             schema.TypeSet,
+				Optional:         true,
+				DiffSuppressFunc: diffSuppressOAuth2ClientProfileDefault,
 				Elem: &schema.Schema{
 					Type:         schema.TypeString,
 					ValidateFunc: validation.StringInSlice([]string{"code", "id_token", "token"}, false),
 				},
 			},
 			"sector_identifier_uri": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "URL using the https scheme to be used in calculating Pseudonymous Identifiers by the OP. The URL references a file with a single JSON array of redirect_uri values.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateURI(false),
+				Description:  "URL using the https scheme to be used in calculating Pseudonymous Identifiers by the OP. The URL references a file with a single JSON array of redirect_uri values.",
 			},
 			"skip_consent": {
 				Type:        schema.TypeBool,
@@ -217,7 +252,7 @@ The JWK x5c parameter MAY be used to provide X.509 representations of keys provi
 				Description:  "SubjectType requested for responses to this Client. The subject_types_supported Discovery parameter contains a list of the supported subject_type values for this server. Valid types include `pairwise` and `public`.",
 			},
 			"scopes": {
-				Type:     schema.TypeList,
+				Type:     schema.TypeSet,
 				Optional: true,
 				Computed: true,
 				Elem: &schema.Schema{
@@ -243,6 +278,11 @@ The JWK x5c parameter MAY be used to provide X.509 representations of keys provi
 				Optional:    true,
 				Description: "TermsOfServiceURI is a URL string that points to a human-readable terms of service document for the client that describes a contractual relationship between the end-user and the client that the end-user accepts when authorizing the client.",
 			},
+			"updated_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the client's last update in RFC 3339 format.",
+			},
 			"userinfo_signed_response_alg": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -251,6 +291,72 @@ The JWK x5c parameter MAY be used to provide X.509 representations of keys provi
 If this is specified, the response will be JWT [JWT] serialized, and signed using JWS.
 The default, if omitted, is for the UserInfo Response to return the Claims as a UTF-8 encoded JSON object using the application/json content-type.`,
 			},
+			"verify": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Requests a token with the client_credentials grant, or the jwt-bearer grant if `jwt_bearer` is set, after the client is created or updated, to make sure Hydra accepts the client's credentials.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"public_endpoint": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+							Description:  "URL of Hydra's public API, which serves the token endpoint at `/oauth2/token`.",
+						},
+						"scope": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Space-delimited scopes to request.",
+						},
+						"private_key_pem": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Sensitive:    true,
+							ValidateFunc: validatePrivateKeyPEM,
+							Description:  "PEM encoded private key to sign the client assertion with, required by `private_key_jwt` clients. The assertion is signed with `token_endpoint_auth_signing_alg` if it is set.",
+						},
+						"jwt_bearer": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "Requests the token with an RFC 7523 JWT assertion of an issuer trusted with `hydra_trusted_oauth2_jwt_grant_issuer` instead of the client_credentials grant.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"issuer": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Issuer of the assertion.",
+									},
+									"subject": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "Subject of the assertion.",
+									},
+									"private_key_pem": {
+										Type:         schema.TypeString,
+										Required:     true,
+										Sensitive:    true,
+										ValidateFunc: validatePrivateKeyPEM,
+										Description:  "PEM encoded private key of the issuer to sign the assertion with.",
+									},
+									"kid": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "Key ID of the issuer's key, set in the header of the assertion.",
+									},
+								},
+							},
+						},
+						"warn_only": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Report a warning instead of failing the apply when Hydra refuses the credentials.",
+						},
+					},
+				},
+			},
 			"authorization_code_grant_access_token_lifespan": {
 				Type:             schema.TypeString,
 				Optional:         true,
@@ -322,11 +428,35 @@ The default, if omitted, is for the UserInfo Response to return the Claims as a
 				DiffSuppressFunc: diffSuppressMatchingDurationStrings,
 			},
 		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffJWKs("jwk", validatePublicJWK),
+			customizeDiffJWKsFromPEM("jwk", "public_key_pem", "certificate_pem"),
+			customizeDiffOAuth2ClientJWKOmitted,
+			customizeDiffOAuth2ClientProfile,
+			customizeDiffOAuth2ClientURIHosts,
+			customizeDiffOAuth2ClientConsistency,
+			customizeDiffOAuth2ClientMetadata,
+			customizeDiffOAuth2ClientVerify,
+			customizeDiffOAuth2ClientUpdatedAt,
+		),
 		CreateContext: createOAuth2ClientResource,
 		ReadContext:   readOAuth2ClientResource,
 		UpdateContext: updateOAuth2ClientResource,
 		DeleteContext: deleteOAuth2ClientResource,
 	}
+	r.StateUpgraders = []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    resourceOAuth2ClientV0().CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeOAuth2ClientStateV0,
+		},
+		{
+			Version: 1,
+			Type:    resourceOAuth2ClientV1().CoreConfigSchema().ImpliedType(),
+			Upgrade: upgradeOAuth2ClientStateV1,
+		},
+	}
+	return r
 }
 
 func createOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -334,9 +464,12 @@ func createOAuth2ClientResource(ctx context.Context, data *schema.ResourceData,
 
 	var oAuth2Client *hydra.OAuth2Client
 
-	client := dataToClient(data)
+	client, err := dataToClient(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	err := retryThrottledHydraAction(func() (*http.Response, error) {
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
 		var err error
 		var resp *http.Response
 		oAuth2Client, resp, err = hydraClient.OAuth2Api.CreateOAuth2Client(ctx).OAuth2Client(*client).Execute()
@@ -346,7 +479,11 @@ func createOAuth2ClientResource(ctx context.Context, data *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	return diag.FromErr(dataFromClient(data, oAuth2Client))
+	if err := dataFromClient(data, oAuth2Client); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return verifyOAuth2Client(ctx, data)
 }
 
 func readOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -363,12 +500,9 @@ func readOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, me
 		return resp, err
 	}, meta.(*ClientConfig).backOff)
 	if err != nil {
-		var genericOpenAPIError *hydra.GenericOpenAPIError
-		if errors.As(err, &genericOpenAPIError) {
-			if apiError, ok := genericOpenAPIError.Model().(hydra.ErrorOAuth2); ok && apiError.StatusCode != nil && *apiError.StatusCode == 401 {
-				data.SetId("")
-				return nil
-			}
+		if isOAuth2ClientNotFound(err) {
+			data.SetId("")
+			return nil
 		}
 
 		return diag.FromErr(err)
@@ -377,12 +511,26 @@ func readOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, me
 	return diag.FromErr(dataFromClient(data, oAuth2Client))
 }
 
+// isOAuth2ClientNotFound reports whether Hydra failed to find a client, which it signals with 401 Unauthorized.
+func isOAuth2ClientNotFound(err error) bool {
+	var genericOpenAPIError *hydra.GenericOpenAPIError
+	if errors.As(err, &genericOpenAPIError) {
+		if apiError, ok := genericOpenAPIError.Model().(hydra.ErrorOAuth2); ok && apiError.StatusCode != nil && *apiError.StatusCode == 401 {
+			return true
+		}
+	}
+	return false
+}
+
 func updateOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	hydraClient := meta.(*ClientConfig).hydraClient
 
-	oAuthClient := dataToClient(data)
+	oAuthClient, err := dataToClient(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	err := retryThrottledHydraAction(func() (*http.Response, error) {
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
 		var err error
 		var resp *http.Response
 		oAuthClient, resp, err = hydraClient.OAuth2Api.SetOAuth2Client(ctx, data.Id()).OAuth2Client(*oAuthClient).Execute()
@@ -393,7 +541,11 @@ func updateOAuth2ClientResource(ctx context.Context, data *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
-	return diag.FromErr(dataFromClient(data, oAuthClient))
+	if err := dataFromClient(data, oAuthClient); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return verifyOAuth2Client(ctx, data)
 }
 
 func deleteOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -406,98 +558,443 @@ func deleteOAuth2ClientResource(ctx context.Context, data *schema.ResourceData,
 	return diag.FromErr(err)
 }
 
-func dataFromClient(data *schema.ResourceData, oAuthClient *hydra.OAuth2Client) error {
-	data.SetId(oAuthClient.GetClientId())
-	data.Set("access_token_strategy", oAuthClient.AccessTokenStrategy)
-	data.Set("allowed_cors_origins", oAuthClient.AllowedCorsOrigins)
-	data.Set("audience", oAuthClient.Audience)
-	data.Set("backchannel_logout_session_required", oAuthClient.BackchannelLogoutSessionRequired)
-	data.Set("backchannel_logout_uri", oAuthClient.GetBackchannelLogoutUri())
-	data.Set("client_id", oAuthClient.GetClientId())
-	data.Set("client_name", oAuthClient.ClientName)
-	if oAuthClient.ClientSecret != nil {
-		data.Set("client_secret", oAuthClient.ClientSecret)
-	}
-	data.Set("client_secret_expires_at", oAuthClient.ClientSecretExpiresAt)
-	data.Set("client_uri", oAuthClient.GetClientUri())
-	data.Set("contacts", oAuthClient.Contacts)
-	data.Set("frontchannel_logout_session_required", oAuthClient.FrontchannelLogoutSessionRequired)
-	data.Set("frontchannel_logout_uri", oAuthClient.GetFrontchannelLogoutUri())
-	data.Set("grant_types", oAuthClient.GrantTypes)
-	jwks := &hydra.JsonWebKeySet{}
-	if err := mapstructure.Decode(oAuthClient.Jwks.(map[string]interface{}), jwks); err != nil {
-		return err
+// oAuth2ClientURIAttributes are the attributes holding URIs which Hydra calls or redirects to.
+var oAuth2ClientURIAttributes = []string{
+	"backchannel_logout_uri",
+	"frontchannel_logout_uri",
+	"jwks_uri",
+	"post_logout_redirect_uris",
+	"redirect_uris",
+	"request_uris",
+	"sector_identifier_uri",
+}
+
+// customizeDiffOAuth2ClientURIHosts checks client URIs against the allowed_uri_hosts configured on the provider.
+func customizeDiffOAuth2ClientURIHosts(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	config, ok := meta.(*ClientConfig)
+	if !ok || len(config.allowedURIHosts) == 0 {
+		return nil
 	}
-	dataFromJWKS(data, jwks, "jwk")
-	data.Set("jwks_uri", oAuthClient.GetJwksUri())
-	data.Set("logo_uri", oAuthClient.GetLogoUri())
-	if metadata, ok := oAuthClient.Metadata.(map[string]interface{}); ok {
-		// Check if any nested maps or non-string values exist in metadata
-		useMetadataJSON := false
-		for _, v := range metadata {
-			switch v.(type) {
-			case string:
+
+	for _, key := range oAuth2ClientURIAttributes {
+		if !diff.NewValueKnown(key) {
+			continue
+		}
+
+		var uris []string
+		switch v := diff.Get(key).(type) {
+		case string:
+			uris = []string{v}
+		case *schema.Set:
+			uris = strSlice(v.List())
+		}
+
+		for _, uri := range uris {
+			u, err := url.Parse(uri)
+			if err != nil || u.Host == "" {
 				continue
-			default:
-				useMetadataJSON = true
+			}
+			if !isAllowedHost(u.Hostname(), config.allowedURIHosts) {
+				return fmt.Errorf("%s: host %q of %q is not one of the allowed_uri_hosts configured on the provider", key, u.Hostname(), uri)
 			}
 		}
-		// If metadata contains nested structures or non-string values, use metadata_json
-		if useMetadataJSON {
-			metadataJSON, err := json.Marshal(metadata)
-			if err != nil {
-				return err
+	}
+
+	return nil
+}
+
+// customizeDiffOAuth2ClientConsistency checks combinations of attributes which are invalid according to
+// OpenID Connect Dynamic Client Registration, so they are reported at plan time instead of by Hydra.
+// Attributes left empty are skipped, since Hydra fills them with defaults.
+func customizeDiffOAuth2ClientConsistency(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	known := func(keys ...string) bool {
+		for _, key := range keys {
+			if !diff.NewValueKnown(key) {
+				return false
 			}
-			data.Set("metadata_json", string(metadataJSON))
-			data.Set("metadata", nil)
-		} else {
-			// If no nested structures or non-string values, use metadata
-			data.Set("metadata", metadata)
-			data.Set("metadata_json", nil)
 		}
-	} else {
-		data.Set("metadata", nil)
-		data.Set("metadata_json", nil)
-	}
-	data.Set("owner", oAuthClient.Owner)
-	data.Set("policy_uri", oAuthClient.GetPolicyUri())
-	data.Set("post_logout_redirect_uris", oAuthClient.PostLogoutRedirectUris)
-	data.Set("redirect_uris", oAuthClient.RedirectUris)
-	data.Set("request_object_signing_alg", oAuthClient.RequestObjectSigningAlg)
-	data.Set("request_uris", oAuthClient.RequestUris)
-	data.Set("response_types", oAuthClient.ResponseTypes)
-	data.Set("sector_identifier_uri", oAuthClient.GetSectorIdentifierUri())
-	data.Set("skip_consent", oAuthClient.SkipConsent)
-	data.Set("subject_type", oAuthClient.SubjectType)
+		return true
+	}
+
+	authMethod := diff.Get("token_endpoint_auth_method").(string)
+	hasJWK := len(diff.Get("jwk").([]interface{})) > 0
+	hasJWKSURI := diff.Get("jwks_uri").(string) != ""
+
+	if known("jwk", "jwks_uri") && hasJWK && hasJWKSURI {
+		return errors.New("jwk and jwks_uri must not be used together, either embed the client's keys or reference them by URL")
+	}
+
+	if known("jwk") {
+		for i, jwk := range diff.Get("jwk").([]interface{}) {
+			if jwk := jwk.(map[string]interface{}); jwk["public_key_pem"] != "" && jwk["certificate_pem"] != "" {
+				return fmt.Errorf("jwk.%d: public_key_pem and certificate_pem must not be used together, the certificate already contains the public key", i)
+			}
+		}
+	}
+
+	if known("token_endpoint_auth_method", "jwk", "jwks_uri") && authMethod == "private_key_jwt" && !hasJWK && !hasJWKSURI {
+		return errors.New(`token_endpoint_auth_method "private_key_jwt" requires the client's public keys, set jwk or jwks_uri`)
+	}
+
+	if known("token_endpoint_auth_method") && authMethod == "none" && isConfigured(diff, "client_secret") {
+		return errors.New(`token_endpoint_auth_method "none" is used by public clients, remove client_secret or use "client_secret_basic" or "client_secret_post"`)
+	}
+
+	if !known("grant_types") {
+		return nil
+	}
+	grantTypes := strSlice(diff.Get("grant_types").(*schema.Set).List())
+
+	if known("response_types") && len(grantTypes) > 0 {
+		for _, responseType := range strSlice(diff.Get("response_types").(*schema.Set).List()) {
+			// Hybrid response types like "code id_token" require the grant types of all their parts.
+			for _, part := range strings.Fields(responseType) {
+				var requiredGrantType string
+				switch part {
+				case "code":
+					requiredGrantType = "authorization_code"
+				case "id_token", "token":
+					requiredGrantType = "implicit"
+				}
+				if requiredGrantType != "" && !slices.Contains(grantTypes, requiredGrantType) {
+					return fmt.Errorf("response type %q requires the %q grant type, add it to grant_types", responseType, requiredGrantType)
+				}
+			}
+		}
+	}
+
+	if known("scopes") && slices.Contains(grantTypes, "refresh_token") {
+		scopes := strSlice(diff.Get("scopes").(*schema.Set).List())
+		if len(scopes) > 0 && !slices.Contains(scopes, "offline_access") && !slices.Contains(scopes, "offline") {
+			return errors.New(`grant type "refresh_token" requires the "offline_access" or "offline" scope, add it to scopes`)
+		}
+	}
+
+	return nil
+}
+
+// customizeDiffOAuth2ClientMetadata validates metadata against the metadata_schema_file configured on the provider.
+func customizeDiffOAuth2ClientMetadata(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	config, ok := meta.(*ClientConfig)
+	if !ok || config.metadataSchema == nil {
+		return nil
+	}
+	if !diff.NewValueKnown("metadata") {
+		return nil
+	}
+
+	var metadata interface{} = map[string]interface{}{}
+	if metadataJSON := diff.Get("metadata").(string); metadataJSON != "" {
+		// Invalid JSON is already reported by the attribute validation.
+		if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+			return nil
+		}
+	}
+
+	if err := config.metadataSchema.Validate(metadata); err != nil {
+		return fmt.Errorf("metadata does not match the metadata_schema_file configured on the provider: %s", err)
+	}
+
+	return nil
+}
+
+// customizeDiffOAuth2ClientUpdatedAt marks updated_at as unknown whenever an existing client is going to be updated.
+func customizeDiffOAuth2ClientUpdatedAt(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if diff.Id() != "" && len(diff.GetChangedKeysPrefix("")) > 0 {
+		return diff.SetNewComputed("updated_at")
+	}
+	return nil
+}
+
+// customizeDiffOAuth2ClientJWKOmitted plans no keys while jwk is left out of configuration. jwk is computed so that keys
+// set from PEM inputs can be planned, which would otherwise keep removed keys in state and leave new clients' keys unknown.
+func customizeDiffOAuth2ClientJWKOmitted(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if rawConfig := diff.GetRawConfig(); !rawConfig.IsNull() {
+		if !rawConfig.IsKnown() {
+			return nil
+		}
+		if configured := rawConfig.GetAttr("jwk"); !configured.IsKnown() || (!configured.IsNull() && configured.LengthInt() > 0) {
+			return nil
+		}
+	} else if _, ok := diff.GetOk("jwk"); ok {
+		return nil
+	}
+	if diff.NewValueKnown("jwk") && len(diff.Get("jwk").([]interface{})) == 0 {
+		return nil
+	}
+	return diff.SetNew("jwk", []interface{}{})
+}
+
+// configReader is implemented by both schema.ResourceData and schema.ResourceDiff.
+type configReader interface {
+	GetRawConfig() cty.Value
+	GetOk(key string) (interface{}, bool)
+}
+
+// isConfigured checks the configuration rather than the plan, since computed attributes keep values Hydra returned in state.
+func isConfigured(d configReader, key string) bool {
+	if rawConfig := d.GetRawConfig(); !rawConfig.IsNull() {
+		return !rawConfig.GetAttr(key).IsNull()
+	}
+	_, ok := d.GetOk(key)
+	return ok
+}
+
+// importOAuth2ClientResource accepts either a client ID or a lookup in the form of
+// `name:<client_name>` or `owner:<owner>/<client_name>` which must match exactly one client.
+func importOAuth2ClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	importID := data.Id()
+
+	var clientName, owner string
+	switch {
+	case strings.HasPrefix(importID, "name:"):
+		clientName = strings.TrimPrefix(importID, "name:")
+	case strings.HasPrefix(importID, "owner:"):
+		var ok bool
+		owner, clientName, ok = strings.Cut(strings.TrimPrefix(importID, "owner:"), "/")
+		if !ok || owner == "" {
+			return nil, fmt.Errorf("invalid import ID %q, expected owner:<owner>/<client_name>", importID)
+		}
+	default:
+		return []*schema.ResourceData{data}, nil
+	}
+	if clientName == "" {
+		return nil, fmt.Errorf("invalid import ID %q, client name must not be empty", importID)
+	}
+
+	client, err := findOAuth2Client(ctx, meta, clientName, owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import %q: %w, import by client ID instead", importID, err)
+	}
+
+	data.SetId(client.GetClientId())
+	return []*schema.ResourceData{data}, nil
+}
+
+// findOAuth2Client looks up the only client with the given name and, if set, owner.
+func findOAuth2Client(ctx context.Context, meta interface{}, clientName, owner string) (*hydra.OAuth2Client, error) {
+	clients, err := listOAuth2Clients(ctx, meta, clientName, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []hydra.OAuth2Client
+	var matchIDs []string
+	for _, client := range clients {
+		if client.GetClientName() != clientName || (owner != "" && client.GetOwner() != owner) {
+			continue
+		}
+		matches = append(matches, client)
+		matchIDs = append(matchIDs, client.GetClientId())
+	}
+
+	lookup := fmt.Sprintf("client_name %q", clientName)
+	if owner != "" {
+		lookup += fmt.Sprintf(" and owner %q", owner)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no OAuth2 client found with %s", lookup)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d OAuth2 clients found with %s (%s)", len(matches), lookup, strings.Join(matchIDs, ", "))
+	}
+}
+
+// listOAuth2Clients lists all clients matching the optional client name and owner filters, following pagination.
+func listOAuth2Clients(ctx context.Context, meta interface{}, clientName, owner string) ([]hydra.OAuth2Client, error) {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	var clients []hydra.OAuth2Client
+	pageToken := ""
+	for {
+		var page []hydra.OAuth2Client
+		var resp *http.Response
+
+		err := retryThrottledHydraAction(func() (*http.Response, error) {
+			var err error
+
+			req := hydraClient.OAuth2Api.ListOAuth2Clients(ctx).PageSize(listPageSize)
+			if pageToken != "" {
+				req = req.PageToken(pageToken)
+			}
+			if clientName != "" {
+				req = req.ClientName(clientName)
+			}
+			if owner != "" {
+				req = req.Owner(owner)
+			}
+			page, resp, err = req.Execute()
+
+			return resp, err
+		}, meta.(*ClientConfig).backOff)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, page...)
+
+		pageToken = nextPageToken(resp)
+		if pageToken == "" || len(page) == 0 {
+			return clients, nil
+		}
+	}
+}
+
+// dataFromClient sets the attributes of the client in state. Resources and data sources which share the mapping
+// but leave attributes out of their schema name them in omitted, any other attribute missing from the schema is an error.
+func dataFromClient(data *schema.ResourceData, oAuthClient *hydra.OAuth2Client, omitted ...string) error {
+	data.SetId(oAuthClient.GetClientId())
+
+	attributes := map[string]interface{}{
+		"access_token_strategy":                oAuthClient.AccessTokenStrategy,
+		"allowed_cors_origins":                 oAuthClient.AllowedCorsOrigins,
+		"audience":                             oAuthClient.Audience,
+		"backchannel_logout_session_required":  oAuthClient.BackchannelLogoutSessionRequired,
+		"backchannel_logout_uri":               oAuthClient.GetBackchannelLogoutUri(),
+		"client_id":                            oAuthClient.GetClientId(),
+		"client_name":                          oAuthClient.ClientName,
+		"client_secret_expires_at":             oAuthClient.ClientSecretExpiresAt,
+		"client_uri":                           oAuthClient.GetClientUri(),
+		"contacts":                             oAuthClient.Contacts,
+		"frontchannel_logout_session_required": oAuthClient.FrontchannelLogoutSessionRequired,
+		"frontchannel_logout_uri":              oAuthClient.GetFrontchannelLogoutUri(),
+		"grant_types":                          oAuthClient.GrantTypes,
+		"jwks_uri":                             oAuthClient.GetJwksUri(),
+		"logo_uri":                             oAuthClient.GetLogoUri(),
+		"owner":                                oAuthClient.Owner,
+		"policy_uri":                           oAuthClient.GetPolicyUri(),
+		"post_logout_redirect_uris":            oAuthClient.PostLogoutRedirectUris,
+		"redirect_uris":                        oAuthClient.RedirectUris,
+		"registration_client_uri":              oAuthClient.GetRegistrationClientUri(),
+		"request_object_signing_alg":           oAuthClient.RequestObjectSigningAlg,
+		"request_uris":                         oAuthClient.RequestUris,
+		"response_types":                       oAuthClient.ResponseTypes,
+		"sector_identifier_uri":                oAuthClient.GetSectorIdentifierUri(),
+		"skip_consent":                         oAuthClient.SkipConsent,
+		"subject_type":                         oAuthClient.SubjectType,
+		"token_endpoint_auth_method":           oAuthClient.TokenEndpointAuthMethod,
+		"token_endpoint_auth_signing_alg":      oAuthClient.TokenEndpointAuthSigningAlg,
+		"tos_uri":                              oAuthClient.GetTosUri(),
+		"userinfo_signed_response_alg":         oAuthClient.UserinfoSignedResponseAlg,
+
+		"authorization_code_grant_access_token_lifespan":  oAuthClient.AuthorizationCodeGrantAccessTokenLifespan,
+		"authorization_code_grant_id_token_lifespan":      oAuthClient.AuthorizationCodeGrantIdTokenLifespan,
+		"authorization_code_grant_refresh_token_lifespan": oAuthClient.AuthorizationCodeGrantRefreshTokenLifespan,
+		"client_credentials_grant_access_token_lifespan":  oAuthClient.ClientCredentialsGrantAccessTokenLifespan,
+		"implicit_grant_access_token_lifespan":            oAuthClient.ImplicitGrantAccessTokenLifespan,
+		"implicit_grant_id_token_lifespan":                oAuthClient.ImplicitGrantIdTokenLifespan,
+		"jwt_bearer_grant_access_token_lifespan":          oAuthClient.JwtBearerGrantAccessTokenLifespan,
+		"refresh_token_grant_access_token_lifespan":       oAuthClient.RefreshTokenGrantAccessTokenLifespan,
+		"refresh_token_grant_id_token_lifespan":           oAuthClient.RefreshTokenGrantIdTokenLifespan,
+		"refresh_token_grant_refresh_token_lifespan":      oAuthClient.RefreshTokenGrantRefreshTokenLifespan,
+	}
+	// Secrets and timestamps which Hydra leaves out of a response keep their value in state.
+	if oAuthClient.ClientSecret != nil {
+		attributes["client_secret"] = oAuthClient.ClientSecret
+	}
+	if oAuthClient.RegistrationAccessToken != nil {
+		attributes["registration_access_token"] = oAuthClient.RegistrationAccessToken
+	}
+	if oAuthClient.CreatedAt != nil {
+		attributes["created_at"] = oAuthClient.CreatedAt.Format(time.RFC3339)
+	}
+	if oAuthClient.UpdatedAt != nil {
+		attributes["updated_at"] = oAuthClient.UpdatedAt.Format(time.RFC3339)
+	}
 	if oAuthClient.Scope == nil {
-		data.Set("scopes", oAuthClient.Scope)
+		attributes["scopes"] = oAuthClient.Scope
 	} else {
-		data.Set("scopes", strings.Split(*oAuthClient.Scope, " "))
-	}
-	data.Set("token_endpoint_auth_method", oAuthClient.TokenEndpointAuthMethod)
-	data.Set("token_endpoint_auth_signing_alg", oAuthClient.TokenEndpointAuthSigningAlg)
-	data.Set("tos_uri", oAuthClient.GetTosUri())
-	data.Set("userinfo_signed_response_alg", oAuthClient.UserinfoSignedResponseAlg)
-	data.Set("authorization_code_grant_access_token_lifespan", oAuthClient.AuthorizationCodeGrantAccessTokenLifespan)
-	data.Set("authorization_code_grant_id_token_lifespan", oAuthClient.AuthorizationCodeGrantIdTokenLifespan)
-	data.Set("authorization_code_grant_refresh_token_lifespan", oAuthClient.AuthorizationCodeGrantRefreshTokenLifespan)
-	data.Set("client_credentials_grant_access_token_lifespan", oAuthClient.ClientCredentialsGrantAccessTokenLifespan)
-	data.Set("implicit_grant_access_token_lifespan", oAuthClient.ImplicitGrantAccessTokenLifespan)
-	data.Set("implicit_grant_id_token_lifespan", oAuthClient.ImplicitGrantIdTokenLifespan)
-	data.Set("jwt_bearer_grant_access_token_lifespan", oAuthClient.JwtBearerGrantAccessTokenLifespan)
-	data.Set("refresh_token_grant_access_token_lifespan", oAuthClient.RefreshTokenGrantAccessTokenLifespan)
-	data.Set("refresh_token_grant_id_token_lifespan", oAuthClient.RefreshTokenGrantIdTokenLifespan)
-	data.Set("refresh_token_grant_refresh_token_lifespan", oAuthClient.RefreshTokenGrantRefreshTokenLifespan)
-	return nil
+		attributes["scopes"] = strings.Split(*oAuthClient.Scope, " ")
+	}
+
+	metadata, err := clientMetadataValue(oAuthClient.Metadata)
+	if err != nil {
+		return err
+	}
+	attributes["metadata"] = metadata
+
+	for _, key := range omitted {
+		delete(attributes, key)
+	}
+
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := data.Set(key, attributes[key]); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if slices.Contains(omitted, "jwk") {
+		return nil
+	}
+	jwks := &hydra.JsonWebKeySet{}
+	if rawJWKS, ok := oAuthClient.Jwks.(map[string]interface{}); ok {
+		if err := mapstructure.Decode(rawJWKS, jwks); err != nil {
+			return err
+		}
+	}
+	return dataFromJWKS(data, jwks, "jwk", "public_key_pem", "certificate_pem")
+}
+
+// clientMetadataValue returns metadata in its normalized form in state, leaving out empty metadata.
+func clientMetadataValue(rawMetadata interface{}) (interface{}, error) {
+	metadata, ok := rawMetadata.(map[string]interface{})
+	if !ok || len(metadata) == 0 {
+		return nil, nil
+	}
+
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return string(metadataJSON), nil
+}
+
+// metadataValueString returns string metadata values as is and JSON-encodes all others.
+func metadataValueString(v interface{}) (string, error) {
+	if str, ok := v.(string); ok {
+		return str, nil
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func validateClientMetadata(val interface{}, key string) (ws []string, errors []error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(val.(string)), &metadata); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a JSON object: %s", key, err))
+	}
+	return
 }
 
-func dataToClient(data *schema.ResourceData) *hydra.OAuth2Client {
+// diffSuppressEquivalentClientMetadata compares metadata as JSON, Hydra doesn't distinguish empty from no metadata.
+func diffSuppressEquivalentClientMetadata(k, old, new string, d *schema.ResourceData) bool {
+	isEmpty := func(metadata string) bool {
+		return metadata == "" || normalizeJSON(metadata) == "{}"
+	}
+	if isEmpty(old) && isEmpty(new) {
+		return true
+	}
+	return diffSuppressEquivalentJSON(k, old, new, d)
+}
+
+func dataToClient(data *schema.ResourceData) (*hydra.OAuth2Client, error) {
 	client := &hydra.OAuth2Client{}
 	if ats, ok := data.GetOk("access_token_strategy"); ok {
 		client.AccessTokenStrategy = ptr(ats.(string))
 	}
-	client.AllowedCorsOrigins = strSlice(data.Get("allowed_cors_origins").([]interface{}))
-	client.Audience = strSlice(data.Get("audience").([]interface{}))
+	client.AllowedCorsOrigins = strSlice(data.Get("allowed_cors_origins").(*schema.Set).List())
+	client.Audience = strSlice(data.Get("audience").(*schema.Set).List())
 	client.SetBackchannelLogoutSessionRequired(data.Get("backchannel_logout_session_required").(bool))
 	client.SetBackchannelLogoutUri(data.Get("backchannel_logout_uri").(string))
 	client.SetClientId(data.Get("client_id").(string))
@@ -509,37 +1006,45 @@ func dataToClient(data *schema.ResourceData) *hydra.OAuth2Client {
 		client.ClientSecretExpiresAt = ptr(int64(csea.(int)))
 	}
 	client.SetClientUri(data.Get("client_uri").(string))
-	client.Contacts = strSlice(data.Get("contacts").([]interface{}))
+	client.Contacts = strSlice(data.Get("contacts").(*schema.Set).List())
 	if flsr, ok := data.GetOk("frontchannel_logout_session_required"); ok {
 		client.FrontchannelLogoutSessionRequired = ptr(flsr.(bool))
 	}
 	client.SetFrontchannelLogoutUri(data.Get("frontchannel_logout_uri").(string))
-	client.GrantTypes = strSlice(data.Get("grant_types").([]interface{}))
+	client.GrantTypes = strSlice(data.Get("grant_types").(*schema.Set).List())
+	if grantTypes, ok := oAuth2ClientProfileDefault(data, "grant_types"); ok {
+		client.GrantTypes = grantTypes
+	}
 	if jwk, ok := data.GetOk("jwk"); ok && jwk != nil {
-		client.Jwks = dataToJWKS(data, "jwk")
+		jwks, err := dataToJWKS(data, "jwk")
+		if err != nil {
+			return nil, err
+		}
+		client.Jwks = jwks
 	}
 	client.SetJwksUri(data.Get("jwks_uri").(string))
 	client.SetLogoUri(data.Get("logo_uri").(string))
-	if metadataJSON, ok := data.GetOk("metadata_json"); ok {
+	if metadataJSON, ok := data.GetOk("metadata"); ok {
 		var metadata map[string]interface{}
-		err := json.Unmarshal([]byte(metadataJSON.(string)), &metadata)
-		if err == nil {
-			client.Metadata = metadata
+		if err := json.Unmarshal([]byte(metadataJSON.(string)), &metadata); err != nil {
+			return nil, fmt.Errorf("metadata: %w", err)
 		}
-	} else if metadata, ok := data.GetOk("metadata"); ok {
-		client.Metadata = metadata.(map[string]interface{})
+		client.Metadata = metadata
 	}
 	if o, ok := data.GetOk("owner"); ok {
 		client.Owner = ptr(o.(string))
 	}
 	client.SetPolicyUri(data.Get("policy_uri").(string))
-	client.PostLogoutRedirectUris = strSlice(data.Get("post_logout_redirect_uris").([]interface{}))
-	client.RedirectUris = strSlice(data.Get("redirect_uris").([]interface{}))
+	client.PostLogoutRedirectUris = strSlice(data.Get("post_logout_redirect_uris").(*schema.Set).List())
+	client.RedirectUris = strSlice(data.Get("redirect_uris").(*schema.Set).List())
 	if rosa, ok := data.GetOk("request_object_signing_alg"); ok {
 		client.RequestObjectSigningAlg = ptr(rosa.(string))
 	}
-	client.RequestUris = strSlice(data.Get("request_uris").([]interface{}))
-	client.ResponseTypes = strSlice(data.Get("response_types").([]interface{}))
+	client.RequestUris = strSlice(data.Get("request_uris").(*schema.Set).List())
+	client.ResponseTypes = strSlice(data.Get("response_types").(*schema.Set).List())
+	if responseTypes, ok := oAuth2ClientProfileDefault(data, "response_types"); ok {
+		client.ResponseTypes = responseTypes
+	}
 	client.SetSectorIdentifierUri(data.Get("sector_identifier_uri").(string))
 	if sc, ok := data.GetOk("skip_consent"); ok {
 		client.SkipConsent = ptr(sc.(bool))
@@ -547,7 +1052,7 @@ func dataToClient(data *schema.ResourceData) *hydra.OAuth2Client {
 	if st, ok := data.GetOk("subject_type"); ok {
 		client.SubjectType = ptr(st.(string))
 	}
-	scopes := strSlice(data.Get("scopes").([]interface{}))
+	scopes := strSlice(data.Get("scopes").(*schema.Set).List())
 	if len(scopes) > 0 {
 		client.Scope = ptr(strings.Join(scopes, " "))
 	}
@@ -591,5 +1096,5 @@ func dataToClient(data *schema.ResourceData) *hydra.OAuth2Client {
 	if rtgrtls, ok := data.GetOk("refresh_token_grant_refresh_token_lifespan"); ok {
 		client.RefreshTokenGrantRefreshTokenLifespan = ptr(rtgrtls.(string))
 	}
-	return client
+	return client, nil
 }
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// oAuth2ClientProfile describes defaults and restrictions for a common type of application, following the OAuth 2.0 Security Best Current Practice.
+type oAuth2ClientProfile struct {
+	// defaults are applied to attributes which are not set in configuration.
+	defaults map[string]interface{}
+	// public clients can't keep a secret and must not authenticate at the token endpoint.
+	public bool
+	// redirects requires at least one redirect URI, otherwise redirect URIs must not be set.
+	redirects bool
+	// customSchemes allows redirects to private-use URI schemes, which only native apps can receive.
+	customSchemes bool
+	// grantTypes lists the grant types the application may use.
+	grantTypes []string
+}
+
+var oAuth2ClientProfiles = map[string]oAuth2ClientProfile{
+	"spa": {
+		defaults: map[string]interface{}{
+			"grant_types":                []string{"authorization_code", "refresh_token"},
+			"response_types":             []string{"code"},
+			"token_endpoint_auth_method": "none",
+		},
+		public:     true,
+		redirects:  true,
+		grantTypes: []string{"authorization_code", "refresh_token"},
+	},
+	"native": {
+		defaults: map[string]interface{}{
+			"grant_types":                []string{"authorization_code", "refresh_token"},
+			"response_types":             []string{"code"},
+			"token_endpoint_auth_method": "none",
+		},
+		public:        true,
+		redirects:     true,
+		customSchemes: true,
+		grantTypes:    []string{"authorization_code", "refresh_token"},
+	},
+	"web": {
+		defaults: map[string]interface{}{
+			"grant_types":                []string{"authorization_code", "refresh_token"},
+			"response_types":             []string{"code"},
+			"token_endpoint_auth_method": "client_secret_basic",
+		},
+		redirects:  true,
+		grantTypes: []string{"authorization_code", "refresh_token", "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+	},
+	"service": {
+		defaults: map[string]interface{}{
+			"grant_types":                []string{"client_credentials"},
+			"token_endpoint_auth_method": "client_secret_basic",
+		},
+		grantTypes: []string{"client_credentials", "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+	},
+}
+
+func oAuth2ClientProfileNames() []string {
+	names := make([]string, 0, len(oAuth2ClientProfiles))
+	for name := range oAuth2ClientProfiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// customizeDiffOAuth2ClientProfile plans the defaults of the selected profile for computed attributes which are not configured
+// and checks the resulting client against the restrictions of the profile.
+func customizeDiffOAuth2ClientProfile(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	name := diff.Get("profile").(string)
+	profile, ok := oAuth2ClientProfiles[name]
+	if !ok {
+		return nil
+	}
+
+	for key, value := range profile.defaults {
+		// grant_types and response_types aren't computed, their defaults are applied when the client is written.
+		if _, ok := value.([]string); ok || isConfigured(diff, key) {
+			continue
+		}
+		if err := diff.SetNew(key, value); err != nil {
+			return err
+		}
+	}
+
+	if diff.NewValueKnown("token_endpoint_auth_method") {
+		authMethod := diff.Get("token_endpoint_auth_method").(string)
+		if profile.public && authMethod != "none" {
+			return fmt.Errorf(`profile %q is a public client which can't keep credentials, token_endpoint_auth_method must be "none"`, name)
+		}
+		if !profile.public && authMethod == "none" {
+			return fmt.Errorf(`profile %q is a confidential client, token_endpoint_auth_method must not be "none"`, name)
+		}
+	}
+
+	if profile.public && isConfigured(diff, "client_secret") {
+		return fmt.Errorf("profile %q is a public client which can't keep credentials, remove client_secret", name)
+	}
+
+	if diff.NewValueKnown("grant_types") {
+		for _, grantType := range strSlice(diff.Get("grant_types").(*schema.Set).List()) {
+			if !slices.Contains(profile.grantTypes, grantType) {
+				return fmt.Errorf("profile %q doesn't allow the %q grant type, allowed grant types are %q", name, grantType, profile.grantTypes)
+			}
+		}
+	}
+
+	if diff.NewValueKnown("response_types") {
+		for _, responseType := range strSlice(diff.Get("response_types").(*schema.Set).List()) {
+			if responseType != "code" {
+				return fmt.Errorf("profile %q only allows the %q response type, implicit flows are deprecated, got %q", name, "code", responseType)
+			}
+		}
+	}
+
+	if diff.NewValueKnown("redirect_uris") {
+		redirectURIs := strSlice(diff.Get("redirect_uris").(*schema.Set).List())
+		if profile.redirects && len(redirectURIs) == 0 {
+			return fmt.Errorf("profile %q requires at least one redirect URI, set redirect_uris", name)
+		}
+		if !profile.redirects && len(redirectURIs) > 0 {
+			return fmt.Errorf("profile %q doesn't use redirects, remove redirect_uris", name)
+		}
+		if !profile.customSchemes {
+			for _, redirectURI := range redirectURIs {
+				u, err := url.Parse(redirectURI)
+				if err == nil && u.Scheme != "https" && u.Scheme != "http" {
+					return fmt.Errorf("profile %q doesn't allow redirects to custom URI schemes, got %q", name, redirectURI)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// oAuth2ClientProfileDefault returns the default of the configured profile for a list attribute which is left out of configuration.
+func oAuth2ClientProfileDefault(data *schema.ResourceData, key string) ([]string, bool) {
+	// Without a profile in configuration Get falls back to state while planning, so the raw configuration is preferred.
+	name, _ := data.Get("profile").(string)
+	if rawConfig := data.GetRawConfig(); !rawConfig.IsNull() && rawConfig.Type().HasAttribute("profile") {
+		name = ""
+		if profile := rawConfig.GetAttr("profile"); profile.IsKnown() && !profile.IsNull() {
+			name = profile.AsString()
+		}
+	}
+	value, ok := oAuth2ClientProfiles[name].defaults[key].([]string)
+	if !ok || isConfigured(data, key) {
+		return nil, false
+	}
+	return value, true
+}
+
+// diffSuppressOAuth2ClientProfileDefault hides the defaults of the profile which Hydra returns for attributes left out of configuration.
+func diffSuppressOAuth2ClientProfileDefault(k, old, new string, d *schema.ResourceData) bool {
+	key, _, _ := strings.Cut(k, ".")
+	value, ok := oAuth2ClientProfileDefault(d, key)
+	if !ok {
+		return false
+	}
+	o, _ := d.GetChange(key)
+	current := strSlice(o.(*schema.Set).List())
+	value = slices.Clone(value)
+	slices.Sort(current)
+	slices.Sort(value)
+	return slices.Equal(current, value)
+}
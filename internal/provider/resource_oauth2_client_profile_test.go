@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceOAuth2Client_profile(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   map[string]interface{}
+		expected map[string]string
+		err      string
+	}{
+		"spa defaults": {
+			config: map[string]interface{}{
+				"profile":       "spa",
+				"redirect_uris": []interface{}{"https://app.example.com/callback"},
+			},
+			expected: map[string]string{
+				"token_endpoint_auth_method": "none",
+			},
+		},
+		"service defaults": {
+			config: map[string]interface{}{
+				"profile": "service",
+			},
+			expected: map[string]string{
+				"token_endpoint_auth_method": "client_secret_basic",
+			},
+		},
+		"explicit attributes override defaults": {
+			config: map[string]interface{}{
+				"profile":                    "web",
+				"grant_types":                []interface{}{"authorization_code"},
+				"redirect_uris":              []interface{}{"https://app.example.com/callback"},
+				"token_endpoint_auth_method": "client_secret_post",
+			},
+			expected: map[string]string{
+				"grant_types.#":              "1",
+				"token_endpoint_auth_method": "client_secret_post",
+			},
+		},
+		"spa with secret": {
+			config: map[string]interface{}{
+				"profile":       "spa",
+				"client_secret": "secret",
+				"redirect_uris": []interface{}{"https://app.example.com/callback"},
+			},
+			err: "remove client_secret",
+		},
+		"spa with custom scheme": {
+			config: map[string]interface{}{
+				"profile":       "spa",
+				"redirect_uris": []interface{}{"com.example.app:/callback"},
+			},
+			err: "custom URI schemes",
+		},
+		"native with custom scheme": {
+			config: map[string]interface{}{
+				"profile":       "native",
+				"redirect_uris": []interface{}{"com.example.app:/callback"},
+			},
+			expected: map[string]string{
+				"token_endpoint_auth_method": "none",
+			},
+		},
+		"web without redirects": {
+			config: map[string]interface{}{
+				"profile": "web",
+			},
+			err: "requires at least one redirect URI",
+		},
+		"web with implicit": {
+			config: map[string]interface{}{
+				"profile":        "web",
+				"grant_types":    []interface{}{"implicit"},
+				"redirect_uris":  []interface{}{"https://app.example.com/callback"},
+				"response_types": []interface{}{"id_token"},
+			},
+			err: `doesn't allow the "implicit" grant type`,
+		},
+		"public service": {
+			config: map[string]interface{}{
+				"profile":                    "service",
+				"token_endpoint_auth_method": "none",
+			},
+			err: "confidential client",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			diff, err := resourceOAuth2Client().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), nil)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			for key, value := range tc.expected {
+				require.Equal(t, value, diff.Attributes[key].New, key)
+			}
+		})
+	}
+}
+
+func TestResourceOAuth2Client_profileDefaults(t *testing.T) {
+	r := resourceOAuth2Client()
+
+	data := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{"profile": "spa"})
+	client, err := dataToClient(data)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"authorization_code", "refresh_token"}, client.GrantTypes)
+	require.Equal(t, []string{"code"}, client.ResponseTypes)
+
+	data = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+	client, err = dataToClient(data)
+	require.NoError(t, err)
+	require.Empty(t, client.GrantTypes)
+	require.Empty(t, client.ResponseTypes)
+
+	data = schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"profile":                    "spa",
+		"grant_types":                []interface{}{"authorization_code", "refresh_token"},
+		"response_types":             []interface{}{"code"},
+		"token_endpoint_auth_method": "none",
+	})
+	data.SetId("client")
+	state := data.State()
+	for name, tc := range map[string]struct {
+		config   map[string]cty.Value
+		expected bool
+	}{
+		"profile defaults": {
+			config: map[string]cty.Value{
+				"profile":       cty.StringVal("spa"),
+				"redirect_uris": cty.SetVal([]cty.Value{cty.StringVal("https://app.example.com/callback")}),
+			},
+		},
+		"without profile": {
+			config:   map[string]cty.Value{"token_endpoint_auth_method": cty.StringVal("none")},
+			expected: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(tc.config))
+			require.NoError(t, err)
+
+			state := state.DeepCopy()
+			state.RawConfig = config
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), nil)
+			require.NoError(t, err)
+			changed := diff != nil && diff.Attributes["grant_types.#"] != nil && diff.Attributes["grant_types.#"].New != diff.Attributes["grant_types.#"].Old
+			require.Equal(t, tc.expected, changed)
+		})
+	}
+}
@@ -0,0 +1,428 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"gopkg.in/yaml.v3"
+)
+
+func resourceOAuth2ClientSet() *schema.Resource {
+	return &schema.Resource{
+		Description: `Manages a set of OAuth 2.0 clients defined in a single YAML or JSON document.
+The document uses the client format of the Hydra CLI, a list of clients or a single client, and every client needs a client_id.
+Clients are reconciled by client_id: clients added to the document are created, changed ones are replaced and removed ones are deleted.`,
+		Importer: &schema.ResourceImporter{
+			StateContext: importOAuth2ClientSetResource,
+		},
+		Schema: map[string]*schema.Schema{
+			"document": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				ValidateFunc:     validateOAuth2ClientSetDocument,
+				DiffSuppressFunc: diffSuppressEquivalentOAuth2ClientSetDocument,
+				Description:      "YAML or JSON document with the clients, usually read with `file`. Sensitive, since it may contain client secrets.",
+			},
+			"clients": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "JSON-encoded clients by client_id, as defined in the document without client_secret. Changes made outside of Terraform show up here.",
+			},
+		},
+		CustomizeDiff: customizeDiffOAuth2ClientSet,
+		CreateContext: createOAuth2ClientSetResource,
+		ReadContext:   readOAuth2ClientSetResource,
+		UpdateContext: updateOAuth2ClientSetResource,
+		DeleteContext: deleteOAuth2ClientSetResource,
+	}
+}
+
+// parseOAuth2ClientSetDocument parses the clients of a document by client_id.
+func parseOAuth2ClientSetDocument(document string) (map[string]map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal([]byte(document), &raw); err != nil {
+		return nil, err
+	}
+
+	var items []interface{}
+	switch v := raw.(type) {
+	case nil:
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items = []interface{}{v}
+	default:
+		return nil, errors.New("expected a list of clients or a single client")
+	}
+
+	clients := make(map[string]map[string]interface{}, len(items))
+	for i, item := range items {
+		client, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("client %d: expected an object", i)
+		}
+
+		clientID, _ := client["client_id"].(string)
+		if clientID == "" {
+			return nil, fmt.Errorf("client %d: client_id is required to reconcile clients", i)
+		}
+		if _, ok := clients[clientID]; ok {
+			return nil, fmt.Errorf("client %d: duplicate client_id %q", i, clientID)
+		}
+
+		// Round trip through JSON, so that values have the same types as in API responses.
+		encoded, err := json.Marshal(client)
+		if err != nil {
+			return nil, fmt.Errorf("client %q: %w", clientID, err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(encoded))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&hydra.OAuth2Client{}); err != nil {
+			return nil, fmt.Errorf("client %q: %w", clientID, err)
+		}
+		client = nil
+		if err := json.Unmarshal(encoded, &client); err != nil {
+			return nil, fmt.Errorf("client %q: %w", clientID, err)
+		}
+
+		clients[clientID] = client
+	}
+
+	return clients, nil
+}
+
+// encodeOAuth2ClientSetClients encodes clients for the clients attribute. Secrets are left out, since Hydra never returns them.
+func encodeOAuth2ClientSetClients(clients map[string]map[string]interface{}) (map[string]interface{}, error) {
+	encoded := make(map[string]interface{}, len(clients))
+	for clientID, client := range clients {
+		withoutSecret := make(map[string]interface{}, len(client))
+		for k, v := range client {
+			if k != "client_secret" {
+				withoutSecret[k] = v
+			}
+		}
+
+		value, err := json.Marshal(withoutSecret)
+		if err != nil {
+			return nil, err
+		}
+		encoded[clientID] = string(value)
+	}
+	return encoded, nil
+}
+
+func validateOAuth2ClientSetDocument(val interface{}, key string) (ws []string, errors []error) {
+	v, ok := val.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", key))
+		return
+	}
+
+	if _, err := parseOAuth2ClientSetDocument(v); err != nil {
+		errors = append(errors, fmt.Errorf("%q is not a valid client document: %s", key, err))
+	}
+	return
+}
+
+func diffSuppressEquivalentOAuth2ClientSetDocument(k, old, new string, d *schema.ResourceData) bool {
+	oldClients, err := parseOAuth2ClientSetDocument(old)
+	if err != nil {
+		return false
+	}
+	newClients, err := parseOAuth2ClientSetDocument(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldClients, newClients)
+}
+
+// customizeDiffOAuth2ClientSet plans the clients attribute from the document, so that every changed client shows up in the plan.
+func customizeDiffOAuth2ClientSet(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("document") {
+		return diff.SetNewComputed("clients")
+	}
+
+	clients, err := parseOAuth2ClientSetDocument(diff.Get("document").(string))
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeOAuth2ClientSetClients(clients)
+	if err != nil {
+		return err
+	}
+	return diff.SetNew("clients", encoded)
+}
+
+func createOAuth2ClientSetResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	clients, err := parseOAuth2ClientSetDocument(data.Get("document").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(id.UniqueId())
+
+	created := make(map[string]map[string]interface{}, len(clients))
+	for _, clientID := range sortedOAuth2ClientIDs(clients) {
+		if err := putOAuth2ClientSetClient(ctx, meta, clients[clientID], false); err != nil {
+			return diag.FromErr(setOAuth2ClientSetClients(data, created, err))
+		}
+		created[clientID] = clients[clientID]
+	}
+
+	return diag.FromErr(setOAuth2ClientSetClients(data, created, nil))
+}
+
+// importOAuth2ClientSetResource accepts the client_ids of the set separated by commas. The document can't be derived
+// from Hydra, so imported clients are replaced with their definition in the document on the next apply,
+// and imported clients which aren't in the document are deleted.
+func importOAuth2ClientSetResource(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	clients := make(map[string]interface{})
+	for _, clientID := range strings.Split(data.Id(), ",") {
+		clientID = strings.TrimSpace(clientID)
+		if clientID == "" {
+			return nil, fmt.Errorf("unexpected ID %q, expected client_ids separated by commas", data.Id())
+		}
+
+		err := retryThrottledHydraAction(func() (*http.Response, error) {
+			_, resp, err := hydraClient.OAuth2Api.GetOAuth2Client(ctx, clientID).Execute()
+			return resp, err
+		}, meta.(*ClientConfig).backOff)
+		if err != nil {
+			return nil, fmt.Errorf("client %q: %w", clientID, err)
+		}
+		clients[clientID] = "{}"
+	}
+
+	data.Set("clients", clients)
+
+	return []*schema.ResourceData{data}, nil
+}
+
+func readOAuth2ClientSetResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	clients := make(map[string]interface{})
+	for clientID, value := range data.Get("clients").(map[string]interface{}) {
+		var desired map[string]interface{}
+		if err := json.Unmarshal([]byte(value.(string)), &desired); err != nil {
+			return diag.FromErr(err)
+		}
+
+		var oAuth2Client *hydra.OAuth2Client
+		err := retryThrottledHydraAction(func() (*http.Response, error) {
+			var resp *http.Response
+			var err error
+
+			oAuth2Client, resp, err = hydraClient.OAuth2Api.GetOAuth2Client(ctx, clientID).Execute()
+
+			return resp, err
+		}, meta.(*ClientConfig).backOff)
+		if err != nil {
+			if isOAuth2ClientNotFound(err) {
+				continue
+			}
+			return diag.FromErr(err)
+		}
+
+		actual, err := projectOAuth2ClientSetClient(oAuth2Client, desired)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		clients[clientID] = actual
+	}
+
+	data.Set("clients", clients)
+
+	return nil
+}
+
+func updateOAuth2ClientSetResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	oldDocument, newDocument := data.GetChange("document")
+	oldClients, err := parseOAuth2ClientSetDocument(oldDocument.(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	newClients, err := parseOAuth2ClientSetDocument(newDocument.(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The prior state holds the clients as they were last read, including changes made outside of Terraform.
+	oldEncoded, _ := data.GetChange("clients")
+	existing := oldEncoded.(map[string]interface{})
+	newEncoded, err := encodeOAuth2ClientSetClients(newClients)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result := make(map[string]map[string]interface{}, len(newClients))
+	for clientID, client := range oldClients {
+		if _, ok := existing[clientID]; ok {
+			result[clientID] = client
+		}
+	}
+
+	// Imported clients are only known from state.
+	managed := make(map[string]map[string]interface{}, len(oldClients)+len(existing))
+	for clientID := range existing {
+		managed[clientID] = nil
+	}
+	for clientID, client := range oldClients {
+		managed[clientID] = client
+	}
+
+	for _, clientID := range sortedOAuth2ClientIDs(managed) {
+		if _, ok := newClients[clientID]; ok {
+			continue
+		}
+		if err := deleteOAuth2ClientSetClient(ctx, meta, clientID); err != nil {
+			return diag.FromErr(setOAuth2ClientSetClients(data, result, err))
+		}
+		delete(result, clientID)
+	}
+
+	for _, clientID := range sortedOAuth2ClientIDs(newClients) {
+		client := newClients[clientID]
+
+		_, exists := existing[clientID]
+		if exists && existing[clientID] == newEncoded[clientID] && equalJSONMaps(oldClients[clientID], client) {
+			continue
+		}
+
+		if err := putOAuth2ClientSetClient(ctx, meta, client, exists); err != nil {
+			return diag.FromErr(setOAuth2ClientSetClients(data, result, err))
+		}
+		result[clientID] = client
+	}
+
+	return diag.FromErr(setOAuth2ClientSetClients(data, result, nil))
+}
+
+func deleteOAuth2ClientSetResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	for clientID := range data.Get("clients").(map[string]interface{}) {
+		if err := deleteOAuth2ClientSetClient(ctx, meta, clientID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return nil
+}
+
+// putOAuth2ClientSetClient creates a client, or replaces all of its fields if it exists.
+func putOAuth2ClientSetClient(ctx context.Context, meta interface{}, client map[string]interface{}, exists bool) error {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	encoded, err := json.Marshal(client)
+	if err != nil {
+		return err
+	}
+	oAuth2Client := hydra.OAuth2Client{}
+	if err := json.Unmarshal(encoded, &oAuth2Client); err != nil {
+		return err
+	}
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		var resp *http.Response
+		var err error
+		if exists {
+			_, resp, err = hydraClient.OAuth2Api.SetOAuth2Client(ctx, oAuth2Client.GetClientId()).OAuth2Client(oAuth2Client).Execute()
+		} else {
+			_, resp, err = hydraClient.OAuth2Api.CreateOAuth2Client(ctx).OAuth2Client(oAuth2Client).Execute()
+		}
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return fmt.Errorf("client %q: %w", oAuth2Client.GetClientId(), err)
+	}
+
+	return nil
+}
+
+func deleteOAuth2ClientSetClient(ctx context.Context, meta interface{}, clientID string) error {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		return hydraClient.OAuth2Api.DeleteOAuth2Client(ctx, clientID).Execute()
+	}, meta.(*ClientConfig).backOff)
+	if err != nil && !isOAuth2ClientNotFound(err) {
+		return fmt.Errorf("client %q: %w", clientID, err)
+	}
+
+	return nil
+}
+
+// setOAuth2ClientSetClients records the clients which were applied, so that a partially applied set is reconciled on the next run.
+func setOAuth2ClientSetClients(data *schema.ResourceData, clients map[string]map[string]interface{}, err error) error {
+	encoded, encodeErr := encodeOAuth2ClientSetClients(clients)
+	if encodeErr != nil {
+		return encodeErr
+	}
+	data.Set("clients", encoded)
+	return err
+}
+
+// projectOAuth2ClientSetClient encodes the fields of the client which are defined in the document.
+// Hydra omits empty fields and normalizes durations, neither of which is a change.
+func projectOAuth2ClientSetClient(oAuth2Client *hydra.OAuth2Client, desired map[string]interface{}) (string, error) {
+	encoded, err := json.Marshal(oAuth2Client)
+	if err != nil {
+		return "", err
+	}
+	var actual map[string]interface{}
+	if err := json.Unmarshal(encoded, &actual); err != nil {
+		return "", err
+	}
+
+	projected := make(map[string]interface{}, len(desired))
+	for k, desiredValue := range desired {
+		actualValue, ok := actual[k]
+		switch {
+		case !ok || actualValue == nil:
+			if isEmptyValue(desiredValue) {
+				projected[k] = desiredValue
+			}
+		case strings.HasSuffix(k, "_lifespan") && matchingDurationValues(desiredValue, actualValue):
+			projected[k] = desiredValue
+		default:
+			projected[k] = actualValue
+		}
+	}
+
+	value, err := json.Marshal(projected)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func matchingDurationValues(a, b interface{}) bool {
+	aStr, aOk := a.(string)
+	bStr, bOk := b.(string)
+	return aOk && bOk && diffSuppressMatchingDurationStrings("", aStr, bStr, nil)
+}
+
+func sortedOAuth2ClientIDs(clients map[string]map[string]interface{}) []string {
+	clientIDs := make([]string, 0, len(clients))
+	for clientID := range clients {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+	return clientIDs
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// oAuth2ClientSetStub keeps clients in memory, like the client endpoints of the Admin API.
+type oAuth2ClientSetStub struct {
+	sync.Mutex
+	clients map[string]map[string]interface{}
+}
+
+func (s *oAuth2ClientSetStub) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var client map[string]interface{}
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(body, &client)
+	}
+
+	clientID := strings.TrimPrefix(req.URL.Path, "/admin/clients/")
+	_, exists := s.clients[clientID]
+	switch {
+	case req.Method == http.MethodPost:
+		clientID = client["client_id"].(string)
+		if _, ok := s.clients[clientID]; ok {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error": "conflict", "status_code": 409}`))
+			return
+		}
+		delete(client, "client_secret")
+		s.clients[clientID] = client
+		w.WriteHeader(http.StatusCreated)
+	case !exists:
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error": "Unable to locate the resource", "status_code": 401}`))
+		return
+	case req.Method == http.MethodPut:
+		delete(client, "client_secret")
+		s.clients[clientID] = client
+	case req.Method == http.MethodDelete:
+		delete(s.clients, clientID)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(s.clients[clientID])
+}
+
+func TestResourceOAuth2ClientSet(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &oAuth2ClientSetStub{clients: map[string]map[string]interface{}{}}
+	meta := newHydraStub(t, stub)
+
+	r := resourceOAuth2ClientSet()
+
+	apply := func(t *testing.T, state *terraform.InstanceState, document string) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{"document": document}), meta)
+		require.NoError(t, err)
+		if diff == nil {
+			return state
+		}
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	var state *terraform.InstanceState
+
+	t.Run("case=creates clients", func(t *testing.T) {
+		state = apply(t, state, `
+- client_id: web
+  client_name: Web
+  client_secret: secret
+  redirect_uris:
+    - https://example.com/callback
+  authorization_code_grant_access_token_lifespan: 1h
+- client_id: service
+  grant_types: [client_credentials]
+`)
+
+		require.Len(t, stub.clients, 2)
+		require.Equal(t, "Web", stub.clients["web"]["client_name"])
+		require.Equal(t, "2", state.Attributes["clients.%"])
+		require.JSONEq(t, `{"client_id": "web", "client_name": "Web", "redirect_uris": ["https://example.com/callback"], "authorization_code_grant_access_token_lifespan": "1h"}`, state.Attributes["clients.web"])
+	})
+
+	t.Run("case=refresh without changes is a no-op", func(t *testing.T) {
+		stub.clients["web"]["authorization_code_grant_access_token_lifespan"] = "1h0m0s"
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, state.Attributes, refreshed.Attributes)
+	})
+
+	t.Run("case=reconciles changes, removals and drift", func(t *testing.T) {
+		stub.clients["service"]["grant_types"] = []interface{}{"authorization_code"}
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+
+		state = apply(t, refreshed, `[
+  {"client_id": "service", "grant_types": ["client_credentials"]},
+  {"client_id": "mobile", "client_name": "Mobile"}
+]`)
+
+		require.Len(t, stub.clients, 2)
+		require.NotContains(t, stub.clients, "web")
+		require.Equal(t, []interface{}{"client_credentials"}, stub.clients["service"]["grant_types"])
+		require.Equal(t, "Mobile", stub.clients["mobile"]["client_name"])
+		require.Equal(t, "2", state.Attributes["clients.%"])
+	})
+
+	t.Run("case=recreates clients deleted outside of Terraform", func(t *testing.T) {
+		delete(stub.clients, "mobile")
+
+		refreshed, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, "1", refreshed.Attributes["clients.%"])
+
+		state = apply(t, refreshed, state.Attributes["document"])
+		require.Contains(t, stub.clients, "mobile")
+	})
+
+	t.Run("case=deletes all clients", func(t *testing.T) {
+		state.Attributes["id"] = state.ID
+		_, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Empty(t, stub.clients)
+	})
+}
+
+func TestResourceOAuth2ClientSet_import(t *testing.T) {
+	ctx := context.Background()
+
+	stub := &oAuth2ClientSetStub{clients: map[string]map[string]interface{}{
+		"web":     {"client_id": "web", "client_name": "Old"},
+		"service": {"client_id": "service"},
+	}}
+	meta := newHydraStub(t, stub)
+
+	r := resourceOAuth2ClientSet()
+	require.True(t, r.Schema["document"].Sensitive)
+
+	_, err := r.Importer.StateContext(ctx, r.Data(&terraform.InstanceState{ID: "web,unknown"}), meta)
+	require.ErrorContains(t, err, `client "unknown"`)
+
+	imported, err := r.Importer.StateContext(ctx, r.Data(&terraform.InstanceState{ID: "web, service"}), meta)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+
+	state, diags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, "2", state.Attributes["clients.%"])
+
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"document": `[{"client_id": "web", "client_name": "Web"}]`,
+	}), meta)
+	require.NoError(t, err)
+	state, diags = r.Apply(ctx, state, diff, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	require.Equal(t, "Web", stub.clients["web"]["client_name"])
+	require.NotContains(t, stub.clients, "service", "imported clients which aren't in the document are deleted")
+	require.Equal(t, "1", state.Attributes["clients.%"])
+}
+
+func TestParseOAuth2ClientSetDocument(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{name: "single client", document: `{"client_id": "a"}`},
+		{name: "empty", document: ``},
+		{name: "missing client_id", document: `[{"client_name": "a"}]`, err: "client_id is required"},
+		{name: "duplicate client_id", document: "- client_id: a\n- client_id: a", err: `duplicate client_id "a"`},
+		{name: "unknown field", document: "- client_id: a\n  redirect_uri: https://example.com", err: `unknown field "redirect_uri"`},
+		{name: "not a list", document: `"a"`, err: "expected a list of clients"},
+	} {
+		t.Run("case="+tc.name, func(t *testing.T) {
+			_, err := parseOAuth2ClientSetDocument(tc.document)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -1,9 +1,22 @@
 package provider
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAccResourceOAuth2Client(t *testing.T) {
@@ -15,12 +28,14 @@ func TestAccResourceOAuth2Client(t *testing.T) {
 				Config: testAccResourceOAuth2PublicClientConfig,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "client_name", "public"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "metadata.first_party", "true"),
+					checkResourceAttrJSON("hydra_oauth2_client.public", "metadata", `{"first_party": true}`),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "redirect_uris.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "redirect_uris.0", "http://localhost:8080/callback"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.public", "redirect_uris.*", "http://localhost:8080/callback"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "response_types.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "response_types.0", "code"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.public", "response_types.*", "code"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.public", "token_endpoint_auth_method", "none"),
+					resource.TestCheckResourceAttrSet("hydra_oauth2_client.public", "created_at"),
+					resource.TestCheckResourceAttrSet("hydra_oauth2_client.public", "updated_at"),
 				),
 			},
 			{
@@ -29,28 +44,375 @@ func TestAccResourceOAuth2Client(t *testing.T) {
 					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "client_name", "secret"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "client_secret", "secret"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "redirect_uris.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "redirect_uris.0", "http://localhost:8080/callback"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.secret", "redirect_uris.*", "http://localhost:8080/callback"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "response_types.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "response_types.0", "code"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.secret", "response_types.*", "code"),
 					resource.TestCheckResourceAttr("hydra_oauth2_client.secret", "token_endpoint_auth_method", "client_secret_post"),
 				),
 			},
 			{
-				Config: testAccResourceOAuth2ClientWithMetadataJSON,
+				ResourceName:            "hydra_oauth2_client.secret",
+				ImportState:             true,
+				ImportStateId:           "name:secret",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"client_secret"},
+			},
+			{
+				Config: testAccResourceOAuth2ClientWithNestedMetadata,
 				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "client_name", "client_with_metadata_json"),
-					checkResourceAttrJSON("hydra_oauth2_client.client_with_metadata_json", "metadata_json", `{"nested": {"key": "value"}, "first_party": true}`),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "redirect_uris.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "redirect_uris.0", "http://localhost:8080/callback"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "response_types.#", "1"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "response_types.0", "code"),
-					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_metadata_json", "token_endpoint_auth_method", "none"),
+					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_nested_metadata", "client_name", "client_with_nested_metadata"),
+					checkResourceAttrJSON("hydra_oauth2_client.client_with_nested_metadata", "metadata", `{"nested": {"key": "value"}, "first_party": true}`),
+					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_nested_metadata", "redirect_uris.#", "1"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.client_with_nested_metadata", "redirect_uris.*", "http://localhost:8080/callback"),
+					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_nested_metadata", "response_types.#", "1"),
+					resource.TestCheckTypeSetElemAttr("hydra_oauth2_client.client_with_nested_metadata", "response_types.*", "code"),
+					resource.TestCheckResourceAttr("hydra_oauth2_client.client_with_nested_metadata", "token_endpoint_auth_method", "none"),
 				),
 			},
 		},
 	})
 }
 
+func TestResourceOAuth2Client_customizeDiff(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"public client": {
+			config: map[string]interface{}{
+				"redirect_uris":              []interface{}{"http://localhost:8080/callback"},
+				"response_types":             []interface{}{"code"},
+				"token_endpoint_auth_method": "none",
+			},
+		},
+		"private_key_jwt without keys": {
+			config: map[string]interface{}{
+				"token_endpoint_auth_method": "private_key_jwt",
+			},
+			err: "requires the client's public keys",
+		},
+		"none with secret": {
+			config: map[string]interface{}{
+				"client_secret":              "secret",
+				"token_endpoint_auth_method": "none",
+			},
+			err: "remove client_secret",
+		},
+		"code without authorization_code": {
+			config: map[string]interface{}{
+				"grant_types":    []interface{}{"client_credentials"},
+				"response_types": []interface{}{"code"},
+			},
+			err: `add it to grant_types`,
+		},
+		"hybrid without implicit": {
+			config: map[string]interface{}{
+				"grant_types":    []interface{}{"authorization_code"},
+				"response_types": []interface{}{"code id_token"},
+			},
+			err: `response type "code id_token" requires the "implicit" grant type`,
+		},
+		"hybrid without authorization_code": {
+			config: map[string]interface{}{
+				"grant_types":    []interface{}{"implicit"},
+				"response_types": []interface{}{"code id_token"},
+			},
+			err: `response type "code id_token" requires the "authorization_code" grant type`,
+		},
+		"hybrid": {
+			config: map[string]interface{}{
+				"grant_types":    []interface{}{"authorization_code", "implicit"},
+				"response_types": []interface{}{"code id_token", "code"},
+			},
+		},
+		"refresh_token without offline_access": {
+			config: map[string]interface{}{
+				"grant_types": []interface{}{"authorization_code", "refresh_token"},
+				"scopes":      []interface{}{"openid"},
+			},
+			err: `"offline_access"`,
+		},
+		"allowed host": {
+			config: map[string]interface{}{
+				"redirect_uris": []interface{}{"https://app.example.com/callback"},
+			},
+		},
+		"disallowed host": {
+			config: map[string]interface{}{
+				"redirect_uris": []interface{}{"https://example.org/callback"},
+			},
+			err: "is not one of the allowed_uri_hosts",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			meta := &ClientConfig{allowedURIHosts: []string{"localhost", "*.example.com"}}
+			_, err := resourceOAuth2Client().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), meta)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestResourceOAuth2Client_metadataSchema(t *testing.T) {
+	metadataSchema, err := jsonschema.Compile("./fixtures/metadata_schema.json")
+	require.NoError(t, err)
+	meta := &ClientConfig{metadataSchema: metadataSchema}
+
+	_, err = resourceOAuth2Client().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"metadata": `{"first_party": true}`,
+	}), meta)
+	require.NoError(t, err)
+
+	_, err = resourceOAuth2Client().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"metadata": `{"first_party": "true"}`,
+	}), meta)
+	require.ErrorContains(t, err, "metadata does not match the metadata_schema_file")
+}
+
+func TestResourceOAuth2Client_updatedAt(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":          "example",
+			"client_id":   "example",
+			"client_name": "example",
+			"created_at":  "2024-01-01T00:00:00Z",
+			"updated_at":  "2024-01-01T00:00:00Z",
+		},
+	}
+
+	diff, err := resourceOAuth2Client().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"client_id":   "example",
+		"client_name": "renamed",
+	}), nil)
+	require.NoError(t, err)
+	require.True(t, diff.Attributes["updated_at"].NewComputed)
+	require.NotContains(t, diff.Attributes, "created_at")
+}
+
+func TestResourceOAuth2Client_jwkPEMInputs(t *testing.T) {
+	data := schema.TestResourceDataRaw(t, resourceOAuth2Client().Schema, map[string]interface{}{
+		"jwk": []interface{}{map[string]interface{}{
+			"alg":            "RS256",
+			"use":            "sig",
+			"public_key_pem": "-----BEGIN PUBLIC KEY-----",
+		}},
+	})
+
+	require.NoError(t, dataFromJWKS(data, &hydra.JsonWebKeySet{Keys: []hydra.JsonWebKey{
+		{Alg: "RS256", Kid: "thumbprint", Kty: "RSA", Use: "sig", N: ptr("n"), E: ptr("AQAB")},
+	}}, "jwk", "public_key_pem", "certificate_pem"))
+
+	require.Equal(t, "thumbprint", data.Get("jwk.0.kid"))
+	require.Equal(t, "-----BEGIN PUBLIC KEY-----", data.Get("jwk.0.public_key_pem"))
+}
+
+func TestResourceOAuth2Client_jwkPlan(t *testing.T) {
+	r := resourceOAuth2Client()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	expected := &hydra.JsonWebKey{Alg: "RS256", Use: "sig"}
+	require.NoError(t, setJWKFromPEM(expected, publicKeyPEM, ""))
+
+	plan := func(jwk map[string]cty.Value) (*terraform.InstanceDiff, error) {
+		config, err := r.CoreConfigSchema().CoerceValue(cty.ObjectVal(map[string]cty.Value{
+			"jwk": cty.ListVal([]cty.Value{cty.ObjectVal(jwk)}),
+		}))
+		require.NoError(t, err)
+
+		// The prior key was set from another PEM encoded public key, so its thumbprint is the kid in state.
+		state := &terraform.InstanceState{
+			ID: "client",
+			Attributes: map[string]string{
+				"id":                   "client",
+				"jwk.#":                "1",
+				"jwk.0.alg":            "RS256",
+				"jwk.0.use":            "sig",
+				"jwk.0.kid":            "previous",
+				"jwk.0.kty":            "RSA",
+				"jwk.0.n":              rfc7638N,
+				"jwk.0.e":              "AQAB",
+				"jwk.0.public_key_pem": "previous",
+			},
+			RawConfig: config,
+		}
+		return r.Diff(context.Background(), state, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), nil)
+	}
+
+	diff, err := plan(map[string]cty.Value{
+		"alg":            cty.StringVal("RS256"),
+		"use":            cty.StringVal("sig"),
+		"public_key_pem": cty.StringVal(publicKeyPEM),
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected.Kid, diff.Attributes["jwk.0.kid"].New)
+	require.Equal(t, expected.GetN(), diff.Attributes["jwk.0.n"].New)
+
+	_, err = plan(map[string]cty.Value{
+		"alg":            cty.StringVal("RS256"),
+		"use":            cty.StringVal("sig"),
+		"public_key_pem": cty.StringVal("-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n"),
+	})
+	require.ErrorContains(t, err, "jwk.0: ")
+
+	_, err = plan(map[string]cty.Value{
+		"alg": cty.StringVal("RS256"),
+		"use": cty.StringVal("sig"),
+		"kid": cty.StringVal("private"),
+		"kty": cty.StringVal("RSA"),
+		"n":   cty.StringVal(rfc7638N),
+		"e":   cty.StringVal("AQAB"),
+		"d":   cty.StringVal("d"),
+	})
+	require.ErrorContains(t, err, `jwk.0: "d" is a private member, only public keys can be registered`)
+
+	config, err := r.CoreConfigSchema().CoerceValue(cty.EmptyObjectVal)
+	require.NoError(t, err)
+	diff, err = r.Diff(context.Background(), &terraform.InstanceState{
+		ID:         "client",
+		Attributes: map[string]string{"id": "client", "jwk.#": "1", "jwk.0.kid": "previous"},
+		RawConfig:  config,
+	}, terraform.NewResourceConfigShimmed(config, r.CoreConfigSchema()), nil)
+	require.NoError(t, err)
+	require.Equal(t, "0", diff.Attributes["jwk.#"].New, "keys left out of configuration are removed")
+}
+
+func TestResourceOAuth2Client_stateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":            "example",
+		"client_name":   "example",
+		"redirect_uris": []interface{}{"http://localhost:8080/callback", "http://localhost:8080/callback"},
+		"scopes":        []interface{}{"openid", "offline_access"},
+	}
+
+	upgraded, err := upgradeOAuth2ClientStateV0(context.Background(), rawState, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id":            "example",
+		"client_name":   "example",
+		"redirect_uris": []interface{}{"http://localhost:8080/callback"},
+		"scopes":        []interface{}{"openid", "offline_access"},
+	}, upgraded)
+	v0 := resourceOAuth2ClientV0().CoreConfigSchema().ImpliedType()
+	require.True(t, v0.AttributeType("redirect_uris").IsListType())
+	require.True(t, v0.AttributeType("metadata").IsMapType())
+	require.False(t, v0.HasAttribute("profile"), "schema version 0 must not follow the current schema")
+}
+
+func TestResourceOAuth2Client_stateUpgradeV1(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rawState map[string]interface{}
+		expected interface{}
+	}{
+		"flat metadata": {
+			rawState: map[string]interface{}{"metadata": map[string]interface{}{"first_party": "true", "team": "core"}},
+			expected: `{"first_party":"true","team":"core"}`,
+		},
+		"metadata_json": {
+			rawState: map[string]interface{}{"metadata": nil, "metadata_json": `{"nested": {"key": "value"}, "first_party": true}`},
+			expected: `{"first_party":true,"nested":{"key":"value"}}`,
+		},
+		"no metadata": {
+			rawState: map[string]interface{}{"metadata": map[string]interface{}{}, "metadata_json": ""},
+			expected: nil,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			upgraded, err := upgradeOAuth2ClientStateV1(context.Background(), tc.rawState, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, upgraded["metadata"])
+			require.NotContains(t, upgraded, "metadata_json")
+		})
+	}
+
+	v1 := resourceOAuth2ClientV1().CoreConfigSchema().ImpliedType()
+	require.True(t, v1.AttributeType("redirect_uris").IsSetType())
+	require.True(t, v1.AttributeType("metadata").IsMapType())
+	require.True(t, v1.HasAttribute("metadata_json"))
+}
+
+func TestResourceOAuth2Client_metadata(t *testing.T) {
+	for name, tc := range map[string]struct {
+		metadata interface{}
+		expected string
+	}{
+		"string values": {
+			metadata: map[string]interface{}{"first_party": "true"},
+			expected: `{"first_party":"true"}`,
+		},
+		"typed and nested values": {
+			metadata: map[string]interface{}{"tier": float64(1), "first_party": true, "nested": map[string]interface{}{"key": "value"}},
+			expected: `{"first_party":true,"nested":{"key":"value"},"tier":1}`,
+		},
+		"empty metadata": {
+			metadata: map[string]interface{}{},
+		},
+		"no metadata": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := schema.TestResourceDataRaw(t, resourceOAuth2Client().Schema, map[string]interface{}{"metadata": `{"previous": true}`})
+			require.NoError(t, dataFromClient(data, &hydra.OAuth2Client{Metadata: tc.metadata}))
+			require.Equal(t, tc.expected, data.Get("metadata"))
+		})
+	}
+
+	r := resourceOAuth2Client()
+	for name, tc := range map[string]struct {
+		state, config string
+		changed       bool
+	}{
+		"formatting and key order": {state: `{"a":1,"b":{"c":true}}`, config: "{\n  \"b\": {\"c\": true},\n  \"a\": 1\n}"},
+		"empty object":             {state: "", config: `{}`},
+		"changed type":             {state: `{"tier":1}`, config: `{"tier":"1"}`, changed: true},
+	} {
+		t.Run("case=diff "+name, func(t *testing.T) {
+			state := &terraform.InstanceState{ID: "client", Attributes: map[string]string{"id": "client", "metadata": tc.state}}
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{"metadata": tc.config}), nil)
+			require.NoError(t, err)
+			if tc.changed {
+				require.NotNil(t, diff)
+				require.Contains(t, diff.Attributes, "metadata")
+			} else if diff != nil {
+				require.NotContains(t, diff.Attributes, "metadata")
+			}
+		})
+	}
+
+	_, errs := validateClientMetadata(`["first_party"]`, "metadata")
+	require.NotEmpty(t, errs)
+}
+
+func TestResourceOAuth2Client_metadataTypes(t *testing.T) {
+	var written map[string]interface{}
+	meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodPut, req.Method, "metadata is written without looking up the client")
+
+		var client hydra.OAuth2Client
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&client))
+		written = client.Metadata.(map[string]interface{})
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(client))
+	}))
+
+	data := schema.TestResourceDataRaw(t, resourceOAuth2Client().Schema, map[string]interface{}{
+		"metadata": `{"first_party": true, "tier": 2, "team": "core", "nested": {"key": "value"}}`,
+	})
+	data.SetId("client")
+
+	diags := updateOAuth2ClientResource(context.Background(), data, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, map[string]interface{}{"first_party": true, "tier": float64(2), "team": "core", "nested": map[string]interface{}{"key": "value"}}, written)
+	require.Equal(t, `{"first_party":true,"nested":{"key":"value"},"team":"core","tier":2}`, data.Get("metadata"))
+}
+
 const (
 	testAccResourceOAuth2PublicClientConfig = `
 provider "hydra" {
@@ -59,9 +421,9 @@ provider "hydra" {
 
 resource "hydra_oauth2_client" "public" {
 	client_name = "public"
-	metadata = {
+	metadata = jsonencode({
 		"first_party" = true
-	}
+	})
 	redirect_uris = ["http://localhost:8080/callback"]
 	response_types = ["code"]
 	token_endpoint_auth_method = "none"
@@ -80,14 +442,14 @@ resource "hydra_oauth2_client" "secret" {
 	token_endpoint_auth_method = "client_secret_post"
 }`
 
-	testAccResourceOAuth2ClientWithMetadataJSON = `
+	testAccResourceOAuth2ClientWithNestedMetadata = `
 provider "hydra" {
   endpoint = "http://localhost:4445"
 }
 
-resource "hydra_oauth2_client" "client_with_metadata_json" {
-	client_name = "client_with_metadata_json"
-	metadata_json = jsonencode({
+resource "hydra_oauth2_client" "client_with_nested_metadata" {
+	client_name = "client_with_nested_metadata"
+	metadata = jsonencode({
 		"nested" = {
 			"key" = "value"
 		},
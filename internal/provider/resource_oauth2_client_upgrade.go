@@ -0,0 +1,464 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// oAuth2ClientSetAttributes are the attributes which used to be lists before schema version 1.
+var oAuth2ClientSetAttributes = []string{
+	"allowed_cors_origins",
+	"audience",
+	"contacts",
+	"grant_types",
+	"post_logout_redirect_uris",
+	"redirect_uris",
+	"request_uris",
+	"response_types",
+	"scopes",
+}
+
+// resourceOAuth2ClientV0 is a frozen copy of schema version 0, in which order-insensitive attributes were lists.
+// Only the types of the attributes matter to decode prior states, so validation and descriptions are left out.
+// It must not change when attributes are added to the current schema.
+func resourceOAuth2ClientV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"access_token_strategy": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"allowed_cors_origins": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"audience": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"backchannel_logout_session_required": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"backchannel_logout_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"client_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_secret": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"client_secret_expires_at": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"client_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"contacts": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"frontchannel_logout_session_required": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"frontchannel_logout_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"grant_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"jwk": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     resourceOAuth2ClientJWKV0(),
+			},
+			"jwks_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"logo_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metadata_json": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metadata": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"owner": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"policy_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"post_logout_redirect_uris": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"redirect_uris": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"request_object_signing_alg": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"request_uris": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"response_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sector_identifier_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"skip_consent": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"subject_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"scopes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"token_endpoint_auth_method": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"token_endpoint_auth_signing_alg": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tos_uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"userinfo_signed_response_alg": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"authorization_code_grant_access_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"authorization_code_grant_id_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"authorization_code_grant_refresh_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_credentials_grant_access_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"implicit_grant_access_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"implicit_grant_id_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"jwt_bearer_grant_access_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"refresh_token_grant_access_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"refresh_token_grant_id_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"refresh_token_grant_refresh_token_lifespan": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceOAuth2ClientJWKV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"alg": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"kid": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"use": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"kty": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"crv": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"d": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"dp": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"dq": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"e": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"k": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"n": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"p": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"q": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"qi": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"x": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"x5c": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"y": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// upgradeOAuth2ClientStateV0 migrates lists to sets. Both share the same JSON representation, so only duplicates need to be removed.
+func upgradeOAuth2ClientStateV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	for _, key := range oAuth2ClientSetAttributes {
+		items, ok := rawState[key].([]interface{})
+		if !ok {
+			continue
+		}
+
+		unique := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if !slices.Contains(unique, item) {
+				unique = append(unique, item)
+			}
+		}
+		rawState[key] = unique
+	}
+	return rawState, nil
+}
+
+// resourceOAuth2ClientV1 is a frozen copy of schema version 1, in which metadata was split into the flat metadata map
+// and metadata_json. It is described as changes to schema version 0 and must not change either.
+func resourceOAuth2ClientV1() *schema.Resource {
+	r := resourceOAuth2ClientV0()
+
+	for _, key := range oAuth2ClientSetAttributes {
+		r.Schema[key].Type = schema.TypeSet
+	}
+	for _, key := range []string{"created_at", "registration_client_uri", "updated_at"} {
+		r.Schema[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	r.Schema["registration_access_token"] = &schema.Schema{
+		Type:      schema.TypeString,
+		Computed:  true,
+		Sensitive: true,
+	}
+	r.Schema["profile"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+
+	jwk := resourceOAuth2ClientJWKV0()
+	for _, key := range []string{"kid", "kty"} {
+		jwk.Schema[key].Required = false
+		jwk.Schema[key].Optional = true
+		jwk.Schema[key].Computed = true
+	}
+	for _, key := range []string{"public_key_pem", "certificate_pem"} {
+		jwk.Schema[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		}
+	}
+	r.Schema["jwk"].Computed = true
+	r.Schema["jwk"].Elem = jwk
+
+	r.Schema["verify"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"public_endpoint": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"scope": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"private_key_pem": {
+					Type:      schema.TypeString,
+					Optional:  true,
+					Sensitive: true,
+				},
+				"jwt_bearer": {
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"issuer": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"subject": {
+								Type:     schema.TypeString,
+								Required: true,
+							},
+							"private_key_pem": {
+								Type:      schema.TypeString,
+								Required:  true,
+								Sensitive: true,
+							},
+							"kid": {
+								Type:     schema.TypeString,
+								Optional: true,
+							},
+						},
+					},
+				},
+				"warn_only": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+			},
+		},
+	}
+
+	return r
+}
+
+// upgradeOAuth2ClientStateV1 merges the flat metadata map and metadata_json into metadata as a JSON object.
+// Values of the flat map are kept as the strings they were stored as, the next refresh reads their type from Hydra.
+func upgradeOAuth2ClientStateV1(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	metadataJSON, _ := rawState["metadata_json"].(string)
+	delete(rawState, "metadata_json")
+
+	if metadataJSON != "" {
+		rawState["metadata"] = normalizeJSON(metadataJSON)
+		return rawState, nil
+	}
+
+	metadata, _ := rawState["metadata"].(map[string]interface{})
+	if len(metadata) == 0 {
+		rawState["metadata"] = nil
+		return rawState, nil
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	rawState["metadata"] = string(encoded)
+	return rawState, nil
+}
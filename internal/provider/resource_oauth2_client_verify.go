@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+// jwtBearerGrantType is the grant type of RFC 7523 section 2.1.
+const jwtBearerGrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// customizeDiffOAuth2ClientVerify rejects verify blocks for clients which can't use the grant verify requests.
+func customizeDiffOAuth2ClientVerify(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("verify") || len(diff.Get("verify").([]interface{})) == 0 {
+		return nil
+	}
+
+	grantType := "client_credentials"
+	if len(diff.Get("verify.0.jwt_bearer").([]interface{})) > 0 {
+		grantType = jwtBearerGrantType
+	}
+
+	if diff.NewValueKnown("token_endpoint_auth_method") {
+		switch diff.Get("token_endpoint_auth_method").(string) {
+		case "none":
+			if grantType == "client_credentials" {
+				return errors.New(`verify uses the client_credentials grant, which public clients with token_endpoint_auth_method "none" can't use`)
+			}
+		case "private_key_jwt":
+			if diff.NewValueKnown("verify.0.private_key_pem") && diff.Get("verify.0.private_key_pem").(string) == "" {
+				return errors.New(`verify requires private_key_pem to sign the client assertion of a "private_key_jwt" client`)
+			}
+		}
+	}
+
+	if diff.NewValueKnown("token_endpoint_auth_signing_alg") && diff.NewValueKnown("verify.0.private_key_pem") {
+		alg := diff.Get("token_endpoint_auth_signing_alg").(string)
+		if privateKeyPEM := diff.Get("verify.0.private_key_pem").(string); alg != "" && privateKeyPEM != "" {
+			privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+			if err != nil {
+				return fmt.Errorf("verify.0.private_key_pem: %w", err)
+			}
+			if _, err := jwsAlgorithm(privateKey.Public(), alg); err != nil {
+				return fmt.Errorf("verify.0.private_key_pem: %w", err)
+			}
+		}
+	}
+
+	if diff.NewValueKnown("grant_types") {
+		grantTypes := strSlice(diff.Get("grant_types").(*schema.Set).List())
+		if len(grantTypes) > 0 && !slices.Contains(grantTypes, grantType) {
+			return fmt.Errorf("verify uses the %s grant, add %q to grant_types", grantType, grantType)
+		}
+	}
+
+	return nil
+}
+
+// verifyOAuth2Client requests a token with the client's credentials, if the client has a verify block.
+func verifyOAuth2Client(ctx context.Context, data *schema.ResourceData) diag.Diagnostics {
+	verify := data.Get("verify").([]interface{})
+	if len(verify) == 0 || verify[0] == nil {
+		return nil
+	}
+	config := verify[0].(map[string]interface{})
+
+	err := requestVerificationToken(ctx, cleanhttp.DefaultClient(), data, config)
+	if err == nil {
+		return nil
+	}
+
+	severity := diag.Error
+	if config["warn_only"].(bool) {
+		severity = diag.Warning
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  fmt.Sprintf("Failed to verify the credentials of OAuth2 client %q", data.Id()),
+		Detail:   err.Error(),
+	}}
+}
+
+// requestVerificationToken requests a token with the client_credentials grant, or with the jwt-bearer grant if configured.
+func requestVerificationToken(ctx context.Context, httpClient *http.Client, data *schema.ResourceData, config map[string]interface{}) error {
+	clientID := data.Get("client_id").(string)
+	tokenURL := strings.TrimSuffix(config["public_endpoint"].(string), "/") + "/oauth2/token"
+
+	form := url.Values{}
+	grantType := "client_credentials"
+	if jwtBearer, _ := config["jwt_bearer"].([]interface{}); len(jwtBearer) > 0 && jwtBearer[0] != nil {
+		grantType = jwtBearerGrantType
+		issuer := jwtBearer[0].(map[string]interface{})
+		assertion, err := signJWTBearerAssertion(issuer, tokenURL)
+		if err != nil {
+			return err
+		}
+		form.Set("assertion", assertion)
+	}
+	form.Set("grant_type", grantType)
+	if scope := config["scope"].(string); scope != "" {
+		form.Set("scope", scope)
+	}
+
+	authMethod := data.Get("token_endpoint_auth_method").(string)
+	switch authMethod {
+	case "client_secret_post":
+		form.Set("client_id", clientID)
+		form.Set("client_secret", data.Get("client_secret").(string))
+	case "private_key_jwt":
+		assertion, err := signClientAssertion(config["private_key_pem"].(string), data.Get("token_endpoint_auth_signing_alg").(string), clientID, tokenURL, data.Get("jwk").([]interface{}))
+		if err != nil {
+			return err
+		}
+		form.Set("client_id", clientID)
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", assertion)
+	case "none":
+		form.Set("client_id", clientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if authMethod == "" || authMethod == "client_secret_basic" {
+		// RFC 6749 section 2.3.1 requires the credentials to be form-encoded before they are used for Basic authentication.
+		req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(data.Get("client_secret").(string)))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var tokenError hydra.ErrorOAuth2
+	if err := json.Unmarshal(body, &tokenError); err != nil || tokenError.GetError() == "" {
+		return fmt.Errorf("token endpoint %s responded with %s", tokenURL, resp.Status)
+	}
+	return fmt.Errorf("token endpoint %s refused the %s grant: %s: %s", tokenURL, grantType, tokenError.GetError(), tokenError.GetErrorDescription())
+}
+
+// signClientAssertion creates the RFC 7523 client assertion of a private_key_jwt client, signed with alg if it is set.
+// The key id is taken from the client's key which matches the private key, if there is one.
+func signClientAssertion(privateKeyPEM, alg, clientID, audience string, jwks []interface{}) (string, error) {
+	privateKey, err := parsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return "", err
+	}
+
+	publicJWK := &hydra.JsonWebKey{}
+	if err := setJWKPublicKey(publicJWK, privateKey.Public()); err != nil {
+		return "", err
+	}
+	thumbprint, err := jwkThumbprint(publicJWK)
+	if err != nil {
+		return "", err
+	}
+	var kid string
+	for _, item := range jwks {
+		jwk, err := dataToJWK(item.(map[string]interface{}))
+		if err != nil {
+			return "", err
+		}
+		if clientThumbprint, err := jwkThumbprint(jwk); err == nil && clientThumbprint == thumbprint {
+			kid = jwk.Kid
+			break
+		}
+	}
+
+	return signJWT(privateKey, alg, kid, clientID, clientID, audience)
+}
+
+// signJWTBearerAssertion creates the RFC 7523 authorization grant assertion of the issuer configured in a jwt_bearer block.
+func signJWTBearerAssertion(issuer map[string]interface{}, audience string) (string, error) {
+	privateKey, err := parsePrivateKeyPEM(issuer["private_key_pem"].(string))
+	if err != nil {
+		return "", err
+	}
+	return signJWT(privateKey, "", issuer["kid"].(string), issuer["issuer"].(string), issuer["subject"].(string), audience)
+}
+
+// signJWT signs the claims of an RFC 7523 assertion, with alg or the algorithm matching the key if alg is empty.
+func signJWT(privateKey crypto.Signer, alg, kid, issuer, subject, audience string) (string, error) {
+	alg, err := jwsAlgorithm(privateKey.Public(), alg)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"iss": issuer,
+		"sub": subject,
+		"aud": audience,
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(5 * time.Minute).Unix(),
+	}
+
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(encodedHeader) + "." + base64.RawURLEncoding.EncodeToString(encodedClaims)
+
+	signature, err := signJWS(privateKey, alg, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// jwsAlgorithms are the JWS algorithms which can be used to sign with a key, by key type and size.
+// The first one is used unless another one is configured.
+func jwsAlgorithms(publicKey crypto.PublicKey) ([]string, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}, nil
+	case *ecdsa.PublicKey:
+		switch key.Curve.Params().BitSize {
+		case 256:
+			return []string{"ES256"}, nil
+		case 384:
+			return []string{"ES384"}, nil
+		case 521:
+			return []string{"ES512"}, nil
+		}
+		return nil, fmt.Errorf("unsupported elliptic curve %q", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return []string{"EdDSA"}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", publicKey)
+}
+
+// jwsAlgorithm checks that alg can be used with the key, or picks the JWS algorithm matching the type and size of the key if alg is empty.
+func jwsAlgorithm(publicKey crypto.PublicKey, alg string) (string, error) {
+	algorithms, err := jwsAlgorithms(publicKey)
+	if err != nil {
+		return "", err
+	}
+	if alg == "" {
+		return algorithms[0], nil
+	}
+	if !slices.Contains(algorithms, alg) {
+		return "", fmt.Errorf("JWS algorithm %q can't be used with the key, expected one of %q", alg, algorithms)
+	}
+	return alg, nil
+}
+
+// jwsHashes are the hash functions of the JWS algorithms of RFC 7518 section 3.1.
+var jwsHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+func signJWS(privateKey crypto.Signer, alg string, signingInput []byte) ([]byte, error) {
+	if alg == "EdDSA" {
+		return privateKey.Sign(rand.Reader, signingInput, crypto.Hash(0))
+	}
+
+	hash, ok := jwsHashes[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signingInput)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "PS":
+		return privateKey.Sign(rand.Reader, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hash})
+	case "ES":
+		// JWS uses the fixed-size concatenation of r and s instead of the ASN.1 encoding of crypto/ecdsa.
+		key := privateKey.(*ecdsa.PrivateKey)
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			return nil, err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...), nil
+	}
+	return privateKey.Sign(rand.Reader, digest, hash)
+}
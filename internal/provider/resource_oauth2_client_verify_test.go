@@ -0,0 +1,288 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceOAuth2Client_verify(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyDER}))
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyDER}))
+
+	var form map[string][]string
+	var username, password string
+	tokenEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/oauth2/token", req.URL.Path)
+		require.NoError(t, req.ParseForm())
+		form = req.PostForm
+		username, password, _ = req.BasicAuth()
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.PostForm.Get("scope") == "admin" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_scope", "error_description": "The client is not allowed to request scope 'admin'."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer"}`))
+	}))
+	defer tokenEndpoint.Close()
+
+	newClient := func(t *testing.T, attrs map[string]interface{}) diag.Diagnostics {
+		data := resourceOAuth2Client().TestResourceData()
+		data.SetId("verified")
+		require.NoError(t, data.Set("client_id", "verified"))
+		for k, v := range attrs {
+			require.NoError(t, data.Set(k, v))
+		}
+		return verifyOAuth2Client(context.Background(), data)
+	}
+
+	t.Run("case=client_secret_basic", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"client_secret": "s3cr=t",
+			"verify":        []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL + "/", "scope": "read"}},
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, "verified", username)
+		require.Equal(t, "s3cr%3Dt", password)
+		require.Equal(t, "client_credentials", form["grant_type"][0])
+		require.Equal(t, "read", form["scope"][0])
+	})
+
+	t.Run("case=client_secret_post", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"client_secret":              "secret",
+			"token_endpoint_auth_method": "client_secret_post",
+			"verify":                     []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL}},
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, "secret", form["client_secret"][0])
+		require.NotContains(t, form, "scope")
+	})
+
+	t.Run("case=private_key_jwt", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"token_endpoint_auth_method": "private_key_jwt",
+			"jwk": []interface{}{map[string]interface{}{
+				"alg":            "ES256",
+				"kid":            "client-key",
+				"use":            "sig",
+				"kty":            "EC",
+				"public_key_pem": publicKeyPEM,
+			}},
+			"verify": []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL, "private_key_pem": privateKeyPEM}},
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", form["client_assertion_type"][0])
+
+		parts := strings.Split(form["client_assertion"][0], ".")
+		require.Len(t, parts, 3)
+
+		var header, claims map[string]interface{}
+		decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, &header))
+		require.Equal(t, "ES256", header["alg"])
+		require.Equal(t, "client-key", header["kid"])
+
+		decoded, err = base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, &claims))
+		require.Equal(t, "verified", claims["sub"])
+		require.Equal(t, tokenEndpoint.URL+"/oauth2/token", claims["aud"])
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		require.Len(t, signature, 64)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		require.True(t, ecdsa.Verify(privateKey.Public().(*ecdsa.PublicKey), digest[:], r, s))
+	})
+
+	t.Run("case=private_key_jwt with token_endpoint_auth_signing_alg", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		rsaKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+
+		diags := newClient(t, map[string]interface{}{
+			"token_endpoint_auth_method":      "private_key_jwt",
+			"token_endpoint_auth_signing_alg": "PS384",
+			"jwks_uri":                        "https://example.com/jwks.json",
+			"verify":                          []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL, "private_key_pem": rsaKeyPEM}},
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+
+		parts := strings.Split(form["client_assertion"][0], ".")
+		require.Len(t, parts, 3)
+		var header map[string]interface{}
+		decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, &header))
+		require.Equal(t, "PS384", header["alg"])
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha512.Sum384([]byte(parts[0] + "." + parts[1]))
+		require.NoError(t, rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA384, digest[:], signature, nil))
+	})
+
+	t.Run("case=jwt_bearer", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"token_endpoint_auth_method": "none",
+			"verify": []interface{}{map[string]interface{}{
+				"public_endpoint": tokenEndpoint.URL,
+				"jwt_bearer": []interface{}{map[string]interface{}{
+					"issuer":          "https://issuer.example.com",
+					"subject":         "alice",
+					"private_key_pem": privateKeyPEM,
+					"kid":             "issuer-key",
+				}},
+			}},
+		})
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Equal(t, jwtBearerGrantType, form["grant_type"][0])
+		require.Equal(t, "verified", form["client_id"][0])
+		require.NotContains(t, form, "client_assertion")
+
+		parts := strings.Split(form["assertion"][0], ".")
+		require.Len(t, parts, 3)
+
+		var header, claims map[string]interface{}
+		decoded, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, &header))
+		require.Equal(t, "ES256", header["alg"])
+		require.Equal(t, "issuer-key", header["kid"])
+
+		decoded, err = base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, &claims))
+		require.Equal(t, "https://issuer.example.com", claims["iss"])
+		require.Equal(t, "alice", claims["sub"])
+		require.Equal(t, tokenEndpoint.URL+"/oauth2/token", claims["aud"])
+	})
+
+	t.Run("case=refused", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"client_secret": "secret",
+			"verify":        []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL, "scope": "admin"}},
+		})
+		require.True(t, diags.HasError())
+		require.Contains(t, diags[0].Detail, "invalid_scope: The client is not allowed to request scope 'admin'.")
+	})
+
+	t.Run("case=refused with warn_only", func(t *testing.T) {
+		diags := newClient(t, map[string]interface{}{
+			"client_secret": "secret",
+			"verify":        []interface{}{map[string]interface{}{"public_endpoint": tokenEndpoint.URL, "scope": "admin", "warn_only": true}},
+		})
+		require.False(t, diags.HasError())
+		require.Len(t, diags, 1)
+		require.Equal(t, diag.Warning, diags[0].Severity)
+	})
+}
+
+func TestResourceOAuth2Client_verifyCustomizeDiff(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecKeyDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	ecKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecKeyDER}))
+
+	for name, tc := range map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"confidential client": {
+			config: map[string]interface{}{
+				"grant_types": []interface{}{"client_credentials"},
+				"verify":      []interface{}{map[string]interface{}{"public_endpoint": "https://hydra.example.com"}},
+			},
+		},
+		"public client": {
+			config: map[string]interface{}{
+				"token_endpoint_auth_method": "none",
+				"verify":                     []interface{}{map[string]interface{}{"public_endpoint": "https://hydra.example.com"}},
+			},
+			err: `token_endpoint_auth_method "none"`,
+		},
+		"private_key_jwt without private key": {
+			config: map[string]interface{}{
+				"token_endpoint_auth_method": "private_key_jwt",
+				"jwks_uri":                   "https://example.com/jwks.json",
+				"verify":                     []interface{}{map[string]interface{}{"public_endpoint": "https://hydra.example.com"}},
+			},
+			err: "requires private_key_pem",
+		},
+		"public client with jwt_bearer": {
+			config: map[string]interface{}{
+				"grant_types":                []interface{}{"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+				"token_endpoint_auth_method": "none",
+				"verify": []interface{}{map[string]interface{}{
+					"public_endpoint": "https://hydra.example.com",
+					"jwt_bearer":      []interface{}{map[string]interface{}{"issuer": "https://issuer.example.com", "subject": "alice", "private_key_pem": ecKeyPEM}},
+				}},
+			},
+		},
+		"jwt_bearer without the grant type": {
+			config: map[string]interface{}{
+				"grant_types": []interface{}{"client_credentials"},
+				"verify": []interface{}{map[string]interface{}{
+					"public_endpoint": "https://hydra.example.com",
+					"jwt_bearer":      []interface{}{map[string]interface{}{"issuer": "https://issuer.example.com", "subject": "alice", "private_key_pem": ecKeyPEM}},
+				}},
+			},
+			err: `add "urn:ietf:params:oauth:grant-type:jwt-bearer" to grant_types`,
+		},
+		"signing alg doesn't match the private key": {
+			config: map[string]interface{}{
+				"token_endpoint_auth_method":      "private_key_jwt",
+				"token_endpoint_auth_signing_alg": "RS256",
+				"jwks_uri":                        "https://example.com/jwks.json",
+				"verify":                          []interface{}{map[string]interface{}{"public_endpoint": "https://hydra.example.com", "private_key_pem": ecKeyPEM}},
+			},
+			err: `JWS algorithm "RS256" can't be used with the key, expected one of ["ES256"]`,
+		},
+		"without client_credentials": {
+			config: map[string]interface{}{
+				"grant_types": []interface{}{"authorization_code"},
+				"verify":      []interface{}{map[string]interface{}{"public_endpoint": "https://hydra.example.com"}},
+			},
+			err: `add "client_credentials" to grant_types`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := resourceOAuth2Client().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), &ClientConfig{})
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+// oidcDynamicClientUnsupportedAttributes can only be managed through the Admin API, Hydra rejects or ignores them in dynamic registrations.
+var oidcDynamicClientUnsupportedAttributes = []string{
+	"access_token_strategy",
+	"authorization_code_grant_access_token_lifespan",
+	"authorization_code_grant_id_token_lifespan",
+	"authorization_code_grant_refresh_token_lifespan",
+	"client_credentials_grant_access_token_lifespan",
+	"implicit_grant_access_token_lifespan",
+	"implicit_grant_id_token_lifespan",
+	"jwt_bearer_grant_access_token_lifespan",
+	"metadata",
+	"refresh_token_grant_access_token_lifespan",
+	"refresh_token_grant_id_token_lifespan",
+	"refresh_token_grant_refresh_token_lifespan",
+	"skip_consent",
+	"verify",
+}
+
+func resourceOIDCDynamicClient() *schema.Resource {
+	s := make(map[string]*schema.Schema)
+	for key, attr := range resourceOAuth2Client().Schema {
+		if !slices.Contains(oidcDynamicClientUnsupportedAttributes, key) {
+			s[key] = attr
+		}
+	}
+
+	s["public_endpoint"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+		Description:  "URL of Hydra's public API, which serves the registration endpoint at `/oauth2/register`.",
+	}
+	s["client_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "ID is the id for this client, chosen by Hydra.",
+	}
+	s["client_secret"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "Secret is the client's secret, generated by Hydra when the client is registered.",
+	}
+	s["registration_access_token"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Sensitive:   true,
+		Description: "OpenID Connect Dynamic Client Registration access token, which is used to read, update and delete the client. Hydra rotates it when the client is updated.",
+	}
+
+	return &schema.Resource{
+		Description: `OAuth 2.0 client registered through OpenID Connect Dynamic Client Registration (RFC 7591 and RFC 7592) on Hydra's public API.
+The client is owned through its registration access token instead of the Admin API, so that it can be handed over to the tenant who uses it.
+Dynamic client registration must be enabled in Hydra with oidc.dynamic_client_registration.enabled.`,
+		Schema: s,
+		CustomizeDiff: customdiff.All(
+			customizeDiffJWKs("jwk", validatePublicJWK),
+			customizeDiffJWKsFromPEM("jwk", "public_key_pem", "certificate_pem"),
+			customizeDiffOAuth2ClientJWKOmitted,
+			customizeDiffOAuth2ClientProfile,
+			customizeDiffOAuth2ClientURIHosts,
+			customizeDiffOAuth2ClientConsistency,
+			customizeDiffOAuth2ClientUpdatedAt,
+		),
+		CreateContext: createOIDCDynamicClientResource,
+		ReadContext:   readOIDCDynamicClientResource,
+		UpdateContext: updateOIDCDynamicClientResource,
+		DeleteContext: deleteOIDCDynamicClientResource,
+	}
+}
+
+// newPublicHydraClient returns a client of Hydra's public API. It doesn't use the provider's authentication, which is meant for the Admin API.
+func newPublicHydraClient(endpoint string) *hydra.APIClient {
+	cfg := hydra.NewConfiguration()
+	cfg.HTTPClient = cleanhttp.DefaultPooledClient()
+	cfg.Servers = hydra.ServerConfigurations{
+		{
+			URL: endpoint,
+		},
+	}
+	return hydra.NewAPIClient(cfg)
+}
+
+// withRegistrationAccessToken authenticates requests to the registration endpoint of a client.
+func withRegistrationAccessToken(ctx context.Context, data *schema.ResourceData) context.Context {
+	return context.WithValue(ctx, hydra.ContextAccessToken, data.Get("registration_access_token").(string))
+}
+
+func dataToOIDCDynamicClient(data *schema.ResourceData) (*hydra.OAuth2Client, error) {
+	client, err := dataToClient(data)
+	if err != nil {
+		return nil, err
+	}
+	// Hydra chooses the ID and secret of dynamically registered clients.
+	client.ClientId = nil
+	client.ClientSecret = nil
+	return client, nil
+}
+
+func createOIDCDynamicClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := newPublicHydraClient(data.Get("public_endpoint").(string))
+
+	var oAuth2Client *hydra.OAuth2Client
+
+	client, err := dataToOIDCDynamicClient(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		var resp *http.Response
+		oAuth2Client, resp, err = hydraClient.OidcApi.CreateOidcDynamicClient(ctx).OAuth2Client(*client).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(dataFromClient(data, oAuth2Client, oidcDynamicClientUnsupportedAttributes...))
+}
+
+func readOIDCDynamicClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := newPublicHydraClient(data.Get("public_endpoint").(string))
+
+	var oAuth2Client *hydra.OAuth2Client
+	var resp *http.Response
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+
+		oAuth2Client, resp, err = hydraClient.OidcApi.GetOidcDynamicClient(withRegistrationAccessToken(ctx, data), data.Id()).Execute()
+
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			data.SetId("")
+			return nil
+		}
+
+		// The registration endpoint also answers with 401 Unauthorized once the client is gone, along with its token.
+		// Only the Admin API can tell that apart from a token which was rejected for another reason.
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			exists, adminErr := oAuth2ClientExists(ctx, meta, data.Id())
+			if adminErr != nil {
+				return diag.Errorf("%s, and the Admin API couldn't confirm whether client %q still exists: %s", err, data.Id(), adminErr)
+			}
+			if !exists {
+				data.SetId("")
+				return nil
+			}
+			return diag.Errorf("the registration endpoint rejected the registration_access_token of client %q, which still exists: %s", data.Id(), err)
+		}
+
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(dataFromClient(data, oAuth2Client, oidcDynamicClientUnsupportedAttributes...))
+}
+
+// oAuth2ClientExists looks a client up through the Admin API.
+func oAuth2ClientExists(ctx context.Context, meta interface{}, clientID string) (bool, error) {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	var resp *http.Response
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		_, resp, err = hydraClient.OAuth2Api.GetOAuth2Client(ctx, clientID).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err == nil {
+		return true, nil
+	}
+	if isOAuth2ClientNotFound(err) || (resp != nil && resp.StatusCode == http.StatusNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
+func updateOIDCDynamicClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := newPublicHydraClient(data.Get("public_endpoint").(string))
+
+	oAuthClient, err := dataToOIDCDynamicClient(data)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		var resp *http.Response
+		oAuthClient, resp, err = hydraClient.OidcApi.SetOidcDynamicClient(withRegistrationAccessToken(ctx, data), data.Id()).OAuth2Client(*oAuthClient).Execute()
+
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(dataFromClient(data, oAuthClient, oidcDynamicClientUnsupportedAttributes...))
+}
+
+func deleteOIDCDynamicClientResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := newPublicHydraClient(data.Get("public_endpoint").(string))
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		return hydraClient.OidcApi.DeleteOidcDynamicClient(withRegistrationAccessToken(ctx, data), data.Id()).Execute()
+	}, meta.(*ClientConfig).backOff)
+
+	return diag.FromErr(err)
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	hydra "github.com/ory/hydra-client-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceOIDCDynamicClient(t *testing.T) {
+	ctx := context.Background()
+
+	var registered map[string]interface{}
+	tokens := 0
+	registrationStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if req.Method == http.MethodPost && req.URL.Path == "/oauth2/register" {
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&registered))
+			require.NotContains(t, registered, "client_id")
+			require.NotContains(t, registered, "client_secret")
+
+			tokens++
+			registered["client_id"] = "generated"
+			response := map[string]interface{}{"client_secret": "generated-secret", "registration_access_token": fmt.Sprintf("token-%d", tokens)}
+			for k, v := range registered {
+				response[k] = v
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		if registered == nil || req.URL.Path != "/oauth2/register/generated" || req.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", tokens) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_token", "status_code": 401}`))
+			return
+		}
+
+		switch req.Method {
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&registered))
+			registered["client_id"] = "generated"
+
+			tokens++
+			response := map[string]interface{}{"registration_access_token": fmt.Sprintf("token-%d", tokens)}
+			for k, v := range registered {
+				response[k] = v
+			}
+			_ = json.NewEncoder(w).Encode(response)
+		case http.MethodDelete:
+			registered = nil
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			_ = json.NewEncoder(w).Encode(registered)
+		}
+	}))
+	defer registrationStub.Close()
+
+	meta := &ClientConfig{}
+	r := resourceOIDCDynamicClient()
+
+	apply := func(t *testing.T, state *terraform.InstanceState, config map[string]interface{}) *terraform.InstanceState {
+		diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+
+		state, diags := r.Apply(ctx, state, diff, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		return state
+	}
+
+	state := apply(t, nil, map[string]interface{}{
+		"public_endpoint": registrationStub.URL,
+		"client_name":     "tenant",
+		"redirect_uris":   []interface{}{"https://tenant.example.com/callback"},
+	})
+	require.Equal(t, "generated", state.ID)
+	require.Equal(t, "generated-secret", state.Attributes["client_secret"])
+	require.Equal(t, "token-1", state.Attributes["registration_access_token"])
+
+	state, diags := r.RefreshWithoutUpgrade(ctx, state, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Equal(t, "tenant", state.Attributes["client_name"])
+	require.Equal(t, "generated-secret", state.Attributes["client_secret"])
+
+	state = apply(t, state, map[string]interface{}{
+		"public_endpoint": registrationStub.URL,
+		"client_name":     "renamed",
+		"redirect_uris":   []interface{}{"https://tenant.example.com/callback"},
+	})
+	require.Equal(t, "renamed", registered["client_name"])
+	require.Equal(t, "token-2", state.Attributes["registration_access_token"])
+
+	state.Attributes["id"] = state.ID
+	_, diags = r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+	require.Nil(t, registered)
+}
+
+func TestResourceOIDCDynamicClient_readMissing(t *testing.T) {
+	ctx := context.Background()
+	r := resourceOIDCDynamicClient()
+
+	for name, tc := range map[string]struct {
+		registrationStatus int
+		adminStatus        int
+		removed            bool
+		err                string
+	}{
+		"not found":                  {registrationStatus: http.StatusNotFound, removed: true},
+		"token rejected, deleted":    {registrationStatus: http.StatusUnauthorized, adminStatus: http.StatusUnauthorized, removed: true},
+		"token rejected, not found":  {registrationStatus: http.StatusUnauthorized, adminStatus: http.StatusNotFound, removed: true},
+		"token rejected, exists":     {registrationStatus: http.StatusUnauthorized, adminStatus: http.StatusOK, err: "rejected the registration_access_token"},
+		"token rejected, admin down": {registrationStatus: http.StatusUnauthorized, adminStatus: http.StatusInternalServerError, err: "couldn't confirm"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			registrationStub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.registrationStatus)
+				_, _ = fmt.Fprintf(w, `{"error": "error", "status_code": %d}`, tc.registrationStatus)
+			}))
+			defer registrationStub.Close()
+
+			meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				require.Equal(t, "/admin/clients/generated", req.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.adminStatus)
+				if tc.adminStatus == http.StatusOK {
+					_, _ = w.Write([]byte(`{"client_id": "generated"}`))
+					return
+				}
+				_, _ = fmt.Fprintf(w, `{"error": "error", "status_code": %d}`, tc.adminStatus)
+			}))
+
+			data := r.TestResourceData()
+			data.SetId("generated")
+			require.NoError(t, data.Set("public_endpoint", registrationStub.URL))
+
+			diags := r.ReadContext(ctx, data, meta)
+			if tc.err != "" {
+				require.True(t, diags.HasError())
+				require.Contains(t, diags[0].Summary, tc.err)
+				return
+			}
+			require.False(t, diags.HasError(), "%v", diags)
+			require.Equal(t, tc.removed, data.Id() == "")
+		})
+	}
+}
+
+func TestResourceOIDCDynamicClient_updatedAtPlan(t *testing.T) {
+	r := resourceOIDCDynamicClient()
+	state := &terraform.InstanceState{
+		ID: "generated",
+		Attributes: map[string]string{
+			"id":              "generated",
+			"client_id":       "generated",
+			"client_name":     "tenant",
+			"public_endpoint": "https://hydra.example.com",
+			"scopes.#":        "1",
+			"scopes.0":        "openid",
+			"updated_at":      "2024-01-01T00:00:00Z",
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		clientName string
+		updated    bool
+	}{
+		"unchanged": {clientName: "tenant"},
+		"changed":   {clientName: "renamed", updated: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"public_endpoint": "https://hydra.example.com",
+				"client_name":     tc.clientName,
+			}), &ClientConfig{})
+			require.NoError(t, err)
+
+			if !tc.updated {
+				require.True(t, diff.Empty(), "%v", diff)
+				return
+			}
+			require.True(t, diff.Attributes["updated_at"].NewComputed)
+		})
+	}
+}
+
+func TestResourceOIDCDynamicClient_read(t *testing.T) {
+	r := resourceOIDCDynamicClient()
+	client := &hydra.OAuth2Client{
+		ClientId:                ptr("generated"),
+		ClientName:              ptr("tenant"),
+		ClientSecretExpiresAt:   ptr(int64(0)),
+		RegistrationAccessToken: ptr("token"),
+		RedirectUris:            []string{"https://tenant.example.com/callback"},
+		Scope:                   ptr("openid offline"),
+		SkipConsent:             ptr(true),
+		AccessTokenStrategy:     ptr("jwt"),
+		Metadata:                map[string]interface{}{"first_party": true},
+
+		AuthorizationCodeGrantAccessTokenLifespan: ptr("1h0m0s"),
+	}
+
+	data := r.TestResourceData()
+	require.NoError(t, dataFromClient(data, client, oidcDynamicClientUnsupportedAttributes...))
+	require.Equal(t, "generated", data.Id())
+	require.Equal(t, "tenant", data.Get("client_name"))
+	require.Equal(t, "token", data.Get("registration_access_token"))
+	require.ElementsMatch(t, []interface{}{"openid", "offline"}, data.Get("scopes").(*schema.Set).List())
+
+	// Attributes which the dynamic client leaves out of its schema must be named, instead of being dropped silently.
+	err := dataFromClient(r.TestResourceData(), client)
+	require.ErrorContains(t, err, "access_token_strategy")
+}
@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	hydra "github.com/ory/hydra-client-go/v2"
+)
+
+func resourceTrustedOAuth2JwtGrantIssuer() *schema.Resource {
+	// Hydra can't update a trust relationship, every change replaces it.
+	jwk := resourceClientJWK()
+	for _, attr := range jwk.Schema {
+		attr.ForceNew = true
+	}
+
+	return &schema.Resource{
+		Description: `Trusts an issuer to sign JWT assertions of the RFC 7523 JWT Profile for OAuth 2.0 Authorization Grants,
+which clients exchange for access tokens with the urn:ietf:params:oauth:grant-type:jwt-bearer grant type.
+The issuer's public key is stored in the JSON Web Key Set named after the issuer.`,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Schema: map[string]*schema.Schema{
+			"issuer": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Issuer of the JWT assertions, matched against their iss claim.",
+			},
+			"subject": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Subject the issuer may issue JWT assertions for, matched against their sub claim. Required unless `allow_any_subject` is true.",
+			},
+			"allow_any_subject": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Allow the issuer to issue JWT assertions for any subject. Conflicts with `subject` if true.",
+			},
+			"scopes": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Scopes which may be requested with JWT assertions of the issuer.",
+			},
+			"expires_at": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IsRFC3339Time,
+				DiffSuppressFunc: diffSuppressEquivalentRFC3339,
+				Description:      "Timestamp in RFC 3339 format after which JWT assertions of the issuer are rejected.",
+			},
+			"jwk": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Elem:        jwk,
+				Description: "Public key JWT assertions of the issuer are verified with.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Timestamp of the trust relationship's creation in RFC 3339 format.",
+			},
+		},
+		CustomizeDiff: customdiff.All(
+			customizeDiffJWKs("jwk", validatePublicJWK),
+			customizeDiffTrustedOAuth2JwtGrantIssuerSubject,
+		),
+		CreateContext: createTrustedOAuth2JwtGrantIssuerResource,
+		ReadContext:   readTrustedOAuth2JwtGrantIssuerResource,
+		DeleteContext: deleteTrustedOAuth2JwtGrantIssuerResource,
+	}
+}
+
+// customizeDiffTrustedOAuth2JwtGrantIssuerSubject requires a subject unless any subject is allowed.
+// allow_any_subject = false is the same as leaving it out, so it doesn't conflict with subject.
+func customizeDiffTrustedOAuth2JwtGrantIssuerSubject(ctx context.Context, diff *schema.ResourceDiff, meta interface{}) error {
+	if !diff.NewValueKnown("subject") || !diff.NewValueKnown("allow_any_subject") {
+		return nil
+	}
+
+	subject := diff.Get("subject").(string)
+	allowAnySubject := diff.Get("allow_any_subject").(bool)
+	switch {
+	case subject == "" && !allowAnySubject:
+		return errors.New("subject is required unless allow_any_subject is true")
+	case subject != "" && allowAnySubject:
+		return errors.New("subject conflicts with allow_any_subject = true")
+	}
+	return nil
+}
+
+func createTrustedOAuth2JwtGrantIssuerResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	expiresAt, err := time.Parse(time.RFC3339, data.Get("expires_at").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	jwks, err := dataToJWKS(data, "jwk")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	trust := hydra.TrustOAuth2JwtGrantIssuer{
+		ExpiresAt: expiresAt,
+		Issuer:    data.Get("issuer").(string),
+		Jwk:       jwks.Keys[0],
+		Scope:     strSlice(data.Get("scopes").(*schema.Set).List()),
+	}
+	if subject, ok := data.GetOk("subject"); ok {
+		trust.Subject = ptr(subject.(string))
+	}
+	if allowAnySubject, ok := data.GetOk("allow_any_subject"); ok {
+		trust.AllowAnySubject = ptr(allowAnySubject.(bool))
+	}
+
+	var issuer *hydra.TrustedOAuth2JwtGrantIssuer
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		var resp *http.Response
+		issuer, resp, err = hydraClient.OAuth2Api.TrustOAuth2JwtGrantIssuer(ctx).TrustOAuth2JwtGrantIssuer(trust).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(issuer.GetId())
+
+	return readTrustedOAuth2JwtGrantIssuerResource(ctx, data, meta)
+}
+
+func readTrustedOAuth2JwtGrantIssuerResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	var issuer *hydra.TrustedOAuth2JwtGrantIssuer
+	var issuerResp *http.Response
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		issuer, issuerResp, err = hydraClient.OAuth2Api.GetTrustedOAuth2JwtGrantIssuer(ctx, data.Id()).Execute()
+		return issuerResp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		if issuerResp != nil && issuerResp.StatusCode == http.StatusNotFound {
+			data.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(err)
+	}
+
+	dataFromTrustedOAuth2JwtGrantIssuer(data, issuer)
+
+	// The trust relationship only references its key, which is stored in a JSON Web Key Set like any other key.
+	var jsonWebKeySet *hydra.JsonWebKeySet
+
+	err = retryThrottledHydraAction(func() (*http.Response, error) {
+		var err error
+		var resp *http.Response
+		jsonWebKeySet, resp, err = hydraClient.JwkApi.GetJsonWebKey(ctx, issuer.PublicKey.GetSet(), issuer.PublicKey.GetKid()).Execute()
+		return resp, err
+	}, meta.(*ClientConfig).backOff)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diag.FromErr(dataFromJWKS(data, jsonWebKeySet, "jwk", "public_key_pem", "certificate_pem"))
+}
+
+func deleteTrustedOAuth2JwtGrantIssuerResource(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hydraClient := meta.(*ClientConfig).hydraClient
+
+	err := retryThrottledHydraAction(func() (*http.Response, error) {
+		return hydraClient.OAuth2Api.DeleteTrustedOAuth2JwtGrantIssuer(ctx, data.Id()).Execute()
+	}, meta.(*ClientConfig).backOff)
+
+	return diag.FromErr(err)
+}
+
+func dataFromTrustedOAuth2JwtGrantIssuer(data *schema.ResourceData, issuer *hydra.TrustedOAuth2JwtGrantIssuer) {
+	data.Set("issuer", issuer.GetIssuer())
+	data.Set("subject", issuer.GetSubject())
+	data.Set("allow_any_subject", issuer.GetAllowAnySubject())
+	data.Set("scopes", issuer.Scope)
+	if issuer.ExpiresAt != nil {
+		// Keep the configured timestamp if Hydra merely formats it differently.
+		expiresAt := issuer.ExpiresAt.Format(time.RFC3339)
+		if !diffSuppressEquivalentRFC3339("expires_at", data.Get("expires_at").(string), expiresAt, data) {
+			data.Set("expires_at", expiresAt)
+		}
+	}
+	if issuer.CreatedAt != nil {
+		data.Set("created_at", issuer.CreatedAt.Format(time.RFC3339))
+	}
+}
+
+// listTrustedOAuth2JwtGrantIssuers follows the pagination of the issuers endpoint.
+// The API client doesn't support its page_token parameter, so requests are made with the client's HTTP client directly.
+func listTrustedOAuth2JwtGrantIssuers(ctx context.Context, meta interface{}, issuer string) ([]hydra.TrustedOAuth2JwtGrantIssuer, error) {
+	cfg := meta.(*ClientConfig).hydraClient.GetConfig()
+
+	basePath, err := cfg.ServerURLWithContext(ctx, "OAuth2ApiService.ListTrustedOAuth2JwtGrantIssuers")
+	if err != nil {
+		return nil, err
+	}
+
+	var issuers []hydra.TrustedOAuth2JwtGrantIssuer
+	pageToken := ""
+	for {
+		var page []hydra.TrustedOAuth2JwtGrantIssuer
+		var resp *http.Response
+
+		err := retryThrottledHydraAction(func() (*http.Response, error) {
+			query := url.Values{}
+			query.Set("page_size", strconv.Itoa(listPageSize))
+			if pageToken != "" {
+				query.Set("page_token", pageToken)
+			}
+			if issuer != "" {
+				query.Set("issuer", issuer)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, basePath+"/admin/trust/grants/jwt-bearer/issuers?"+query.Encode(), nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Accept", "application/json")
+			req.Header.Set("User-Agent", cfg.UserAgent)
+			for header, value := range cfg.DefaultHeader {
+				req.Header.Set(header, value)
+			}
+
+			resp, err = cfg.HTTPClient.Do(req)
+			if err != nil {
+				return resp, err
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return resp, err
+			}
+			if resp.StatusCode >= 300 {
+				return resp, fmt.Errorf("failed to list trusted OAuth2 JWT grant issuers: %s: %s", resp.Status, body)
+			}
+
+			page = nil
+			return resp, json.Unmarshal(body, &page)
+		}, meta.(*ClientConfig).backOff)
+		if err != nil {
+			return nil, err
+		}
+
+		issuers = append(issuers, page...)
+
+		pageToken = nextPageToken(resp)
+		if pageToken == "" || len(page) == 0 {
+			return issuers, nil
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceTrustedOAuth2JwtGrantIssuer(t *testing.T) {
+	ctx := context.Background()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(privateKey.Public())
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	var trusted, key map[string]interface{}
+	meta := newHydraStub(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == "/admin/trust/grants/jwt-bearer/issuers":
+			var trust map[string]interface{}
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&trust))
+			key = trust["jwk"].(map[string]interface{})
+			trusted = map[string]interface{}{
+				"id":         "grant",
+				"issuer":     trust["issuer"],
+				"subject":    trust["subject"],
+				"scope":      trust["scope"],
+				"expires_at": "2030-01-01T01:00:00.000+01:00",
+				"created_at": "2024-01-01T00:00:00Z",
+				"public_key": map[string]interface{}{"set": trust["issuer"], "kid": key["kid"]},
+			}
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(trusted)
+		case trusted == nil:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": "not_found", "status_code": 404}`))
+		case req.URL.Path == "/admin/trust/grants/jwt-bearer/issuers/grant" && req.Method == http.MethodDelete:
+			trusted = nil
+			w.WriteHeader(http.StatusNoContent)
+		case req.URL.Path == "/admin/trust/grants/jwt-bearer/issuers/grant":
+			_ = json.NewEncoder(w).Encode(trusted)
+		case req.URL.Path == "/admin/keys/https://idp.example.com/"+key["kid"].(string):
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{key}})
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	r := resourceTrustedOAuth2JwtGrantIssuer()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"issuer":     "https://idp.example.com",
+		"subject":    "alice",
+		"scopes":     []interface{}{"read"},
+		"expires_at": "2030-01-01T00:00:00Z",
+		"jwk": []interface{}{map[string]interface{}{
+			"alg":            "ES256",
+			"use":            "sig",
+			"public_key_pem": publicKeyPEM,
+		}},
+	})
+
+	diff, err := r.Diff(ctx, nil, config, meta)
+	require.NoError(t, err)
+	state, diags := r.Apply(ctx, nil, diff, meta)
+	require.False(t, diags.HasError(), "%v", diags)
+
+	require.Equal(t, "grant", state.ID)
+	require.Equal(t, "EC", key["kty"])
+	require.Equal(t, []interface{}{"read"}, trusted["scope"])
+	require.Equal(t, "2030-01-01T00:00:00Z", state.Attributes["expires_at"])
+	require.Equal(t, "2024-01-01T00:00:00Z", state.Attributes["created_at"])
+	require.Equal(t, key["kid"], state.Attributes["jwk.0.kid"])
+	require.Equal(t, publicKeyPEM, state.Attributes["jwk.0.public_key_pem"])
+
+	diff, err = r.Diff(ctx, state, config, meta)
+	require.NoError(t, err)
+	require.Nil(t, diff)
+
+	t.Run("case=import", func(t *testing.T) {
+		imported := r.Data(&terraform.InstanceState{ID: "grant"})
+		require.False(t, r.ReadContext(ctx, imported, meta).HasError())
+		require.Equal(t, "https://idp.example.com", imported.Get("issuer"))
+		require.Equal(t, "alice", imported.Get("subject"))
+		require.Equal(t, key["x"], imported.Get("jwk.0.x"))
+
+		// Hydra doesn't return public_key_pem, which must not replace the imported trust relationship.
+		diff, err := r.Diff(ctx, imported.State(), config, meta)
+		require.NoError(t, err)
+		require.Nil(t, diff)
+
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(other.Public())
+		require.NoError(t, err)
+		otherConfig := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"issuer":     "https://idp.example.com",
+			"subject":    "alice",
+			"scopes":     []interface{}{"read"},
+			"expires_at": "2030-01-01T00:00:00Z",
+			"jwk": []interface{}{map[string]interface{}{
+				"alg":            "ES256",
+				"use":            "sig",
+				"public_key_pem": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			}},
+		})
+		diff, err = r.Diff(ctx, imported.State(), otherConfig, meta)
+		require.NoError(t, err)
+		require.True(t, diff.RequiresNew())
+	})
+
+	t.Run("case=subject", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			config map[string]interface{}
+			err    string
+		}{
+			"subject":                 {config: map[string]interface{}{"subject": "alice"}},
+			"subject without any":     {config: map[string]interface{}{"subject": "alice", "allow_any_subject": false}},
+			"any subject":             {config: map[string]interface{}{"allow_any_subject": true}},
+			"missing subject":         {config: map[string]interface{}{}, err: "subject is required"},
+			"missing subject not any": {config: map[string]interface{}{"allow_any_subject": false}, err: "subject is required"},
+			"subject and any subject": {config: map[string]interface{}{"subject": "alice", "allow_any_subject": true}, err: "conflicts with allow_any_subject"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				raw := map[string]interface{}{
+					"issuer":     "https://idp.example.com",
+					"expires_at": "2030-01-01T00:00:00Z",
+					"jwk": []interface{}{map[string]interface{}{
+						"alg":            "ES256",
+						"use":            "sig",
+						"public_key_pem": publicKeyPEM,
+					}},
+				}
+				for k, v := range tc.config {
+					raw[k] = v
+				}
+
+				_, err := r.Diff(ctx, nil, terraform.NewResourceConfigRaw(raw), meta)
+				if tc.err == "" {
+					require.NoError(t, err)
+				} else {
+					require.ErrorContains(t, err, tc.err)
+				}
+			})
+		}
+	})
+
+	t.Run("case=delete", func(t *testing.T) {
+		state.Attributes["id"] = state.ID
+		_, diags := r.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		require.False(t, diags.HasError(), "%v", diags)
+		require.Nil(t, trusted)
+
+		imported := r.Data(&terraform.InstanceState{ID: "grant"})
+		require.False(t, r.ReadContext(ctx, imported, meta).HasError())
+		require.Empty(t, imported.Id())
+	})
+}
+
+func TestListTrustedOAuth2JwtGrantIssuers(t *testing.T) {
+	var pageTokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "/admin/trust/grants/jwt-bearer/issuers", req.URL.Path)
+		require.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+		require.False(t, req.URL.Query().Has("issuer"))
+
+		pageToken := req.URL.Query().Get("page_token")
+		pageTokens = append(pageTokens, pageToken)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch pageToken {
+		case "":
+			w.Header().Set("Link", `</admin/trust/grants/jwt-bearer/issuers?page_size=100&page_token=first>; rel="first",</admin/trust/grants/jwt-bearer/issuers?page_size=100&page_token=second>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id": "a", "issuer": "https://a.example.com"}]`))
+		case "second":
+			w.Header().Set("Link", `</admin/trust/grants/jwt-bearer/issuers?page_size=100&page_token=third>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id": "b", "issuer": "https://b.example.com"}]`))
+		case "third":
+			_, _ = w.Write([]byte(`[{"id": "c", "issuer": "https://c.example.com"}]`))
+		default:
+			t.Errorf("unexpected page token %q", pageToken)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	meta := configureTestProvider(t, map[string]interface{}{
+		"endpoint": server.URL,
+		"authentication": []interface{}{map[string]interface{}{
+			"http_header": []interface{}{map[string]interface{}{
+				"name":  "X-Api-Key",
+				"value": "secret",
+			}},
+		}},
+	})
+
+	issuers, err := listTrustedOAuth2JwtGrantIssuers(context.Background(), meta, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "second", "third"}, pageTokens)
+	require.Len(t, issuers, 3)
+	for i, id := range []string{"a", "b", "c"} {
+		require.Equal(t, id, issuers[i].GetId())
+	}
+}
@@ -1,6 +1,13 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 
 	"github.com/svrakitin/terraform-provider-hydra/internal/provider"
@@ -9,5 +16,47 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		if err := generate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	plugin.Serve(&plugin.ServeOpts{ProviderFunc: provider.New})
 }
+
+// generate writes Terraform configuration with import blocks for the resources of an existing Hydra instance.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := flags.String("out", "", "File to write the generated configuration to. Defaults to stdout.")
+	endpoint := flags.String("endpoint", os.Getenv("HYDRA_ADMIN_URL"), "Hydra Admin API URL. Defaults to HYDRA_ADMIN_URL.")
+	jwks := flags.String("jwks", "", "Comma-separated names of JSON Web Key Sets to include.")
+	_ = flags.Parse(args)
+
+	if *endpoint == "" {
+		return fmt.Errorf("endpoint must be set with -endpoint or HYDRA_ADMIN_URL")
+	}
+
+	opts := provider.GenerateOptions{
+		Endpoint: *endpoint,
+	}
+	for _, name := range strings.Split(*jwks, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			opts.JWKSNames = append(opts.JWKSNames, name)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return provider.Generate(context.Background(), w, opts)
+}